@@ -18,10 +18,12 @@ package alerts
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"strings"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/teams"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
@@ -32,12 +34,10 @@ import (
 )
 
 var options struct {
-	high       bool
-	low        bool
 	assignment string
 	columns    string
-	incidentID bool
-	status     string
+	view       string
+	team       string
 }
 
 var Cmd = &cobra.Command{
@@ -61,8 +61,24 @@ func init() {
 	Cmd.Flags().StringVar(
 		&options.columns,
 		"columns",
-		"incident.id,alert.id,cluster.name,alert,cluster.id,status,severity",
-		"Specify which columns to display separated by commas without any space in between",
+		pdcli.DefaultAlertColumns,
+		"Specify which columns to display separated by commas without any space in between, or the name of a column preset of the configuration file.\nAvailable columns: "+strings.Join(pdcli.ColumnKeys(), ", "),
+	)
+
+	// Saved view
+	Cmd.Flags().StringVar(
+		&options.view,
+		"view",
+		"",
+		"Name of a saved view from the configuration file, the assigned-to and columns options take precedence over the view.",
+	)
+
+	// Single team
+	Cmd.Flags().StringVar(
+		&options.team,
+		"team",
+		"",
+		"View alerts of a single team, by name or ID, instead of the teams selected with 'kite teams'.",
 	)
 }
 
@@ -74,14 +90,80 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 		alerts         []pdcli.Alert
 		incidentID     string
 		incidentOpts   pdApi.ListIncidentsOptions
-		teams          []string
-		users          []string
-		status         []string
+		view           *config.View
 
 		//UI
 		tui ui.TUI
 	)
 
+	// The single team replaces the assignment, they can't be both given
+	if options.team != "" && cmd.Flags().Changed("assigned-to") {
+		return utils.NewExitError(utils.ExitUsage, "the --team and --assigned-to flags can't be used together")
+	}
+
+	// Load the configuration file
+	cfg, err := config.Load()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if options.view != "" {
+		view, err = cfg.View(options.view)
+
+		if err != nil {
+			return err
+		}
+
+		if view.AssignedTo != "" && !cmd.Flags().Changed("assigned-to") {
+			options.assignment = view.AssignedTo
+		}
+
+		if view.Columns != "" && !cmd.Flags().Changed("columns") {
+			options.columns = cfg.Columns(view.Columns)
+		}
+
+		// Validate the view filters and sort order before doing any API calls
+		_, err = pdcli.ParseFilters(view.Filters)
+
+		if err != nil {
+			return err
+		}
+
+		err = pdcli.SortAlerts(nil, view.Sort)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// Set the timestamps display
+	err = utils.SetTimeDisplay(cfg.Timezone, cfg.RelativeTimes)
+
+	if err != nil {
+		return err
+	}
+
+	// Set the incidents hidden from the team alerts
+	err = pdcli.SetSuppression(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	// Validate the columns, or the columns of the preset, before doing any API calls
+	options.columns = cfg.Columns(options.columns)
+
+	_, err = pdcli.ParseColumns(options.columns)
+
+	if err != nil {
+		return err
+	}
+
 	// Setup TUI
 	tui.Init()
 	utils.InfoLogger.Print("Initialized terminal UI")
@@ -94,7 +176,7 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 
 	// Create a new pagerduty client
 	utils.InfoLogger.Print("Connecting to PagerDuty API")
-	client, err := client.NewClient().Connect()
+	client, err := client.NewClient(cfg).Connect()
 
 	if err != nil {
 		return err
@@ -109,11 +191,33 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Restrict the team alerts to the given team
+	if options.team != "" {
+		team, err := teams.ResolveTeam(user, options.team)
+
+		if err != nil {
+			return err
+		}
+
+		teamCfg := *cfg
+		teamCfg.Teams = []config.Team{team}
+		cfg = &teamCfg
+		options.assignment = "team"
+	}
+
+	// Display the team of each alert when the alerts of more than one team are displayed
+	if options.assignment == "team" && len(cfg.Teams) > 1 && !cmd.Flags().Changed("columns") && (view == nil || view.Columns == "") {
+		options.columns += "," + pdcli.TeamColumn
+	}
+
 	// UI internals
 	tui.Client = client
+	tui.Config = cfg
+	tui.User = user
 	tui.Username = user.Name
 	tui.Columns = options.columns
 	tui.Role = user.Role
+	tui.Assignment = options.assignment
 
 	// Check for incident ID argument
 	if len(args) > 0 {
@@ -152,72 +256,37 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Set incidents urgency
-	utils.InfoLogger.Printf("Incidents urgency set to: %s, %s", constants.StatusLow, constants.StatusHigh)
-	incidentOpts.Urgencies = []string{constants.StatusLow, constants.StatusHigh}
-
-	// Check the assigned-to flag
-	switch options.assignment {
-
-	case "team":
-		// Load the configuration file
-		cfg, err := config.Load()
-
-		if err != nil {
-			return err
-		}
-
-		teamID := cfg.TeamID
-		tui.AssignedTo = cfg.Team
-
-		if teamID == "" {
-			return fmt.Errorf("no team selected, please run 'kite teams' to set a team")
-		}
-
-		// Fetch incidents belonging to a specific team
-		incidentOpts.TeamIDs = append(teams, teamID)
-		utils.InfoLogger.Printf("Retrieving incidents assigned to team: %s", cfg.Team)
-
-		// Fetch incidents with the following statuses
-		incidentOpts.Statuses = append(status, constants.StatusTriggered, constants.StatusAcknowledged)
-		utils.InfoLogger.Printf("Retrieving incidents with status: %s, %s", constants.StatusTriggered, constants.StatusAcknowledged)
-
-	case "silentTest":
-		// Fetch incidents assigned to silent test
-		tui.AssignedTo = "Silent Test"
-		incidentOpts.UserIDs = append(users, constants.SilentTest)
-		utils.InfoLogger.Printf("Retrieving incidents assigned to Silent Test")
-
-		// Fetch incidents with the following statuses
-		incidentOpts.Statuses = append(status, constants.StatusTriggered, constants.StatusAcknowledged)
-		utils.InfoLogger.Printf("Retrieving incidents with status: %s, %s", constants.StatusTriggered, constants.StatusAcknowledged)
-
-	case "self":
-		// Fetch incidents only assigned to self
-		tui.AssignedTo = user.Name
-		incidentOpts.UserIDs = append(users, user.ID)
-		utils.InfoLogger.Printf("Retrieving incidents assigned to: %s", user.Name)
-
-		// Fetch only acknowledged incidents when option is self (default)
-		incidentOpts.Statuses = append(status, constants.StatusAcknowledged)
-		utils.InfoLogger.Printf("Retrieving incidents with status: %s", constants.StatusAcknowledged)
+	// Check the assigned-to flag and the saved view
+	if view != nil {
+		incidentOpts, tui.AssignedTo, err = pdcli.ViewOptions(*view, options.assignment, user, cfg)
+		tui.ActiveView = view.Name
+		tui.Statuses = view.Statuses
+		tui.Filters = view.Filters
+		tui.SortBy = view.Sort
+		utils.InfoLogger.Printf("Using saved view: %s", view.Name)
+	} else {
+		incidentOpts, tui.AssignedTo, err = pdcli.AssignmentOptions(options.assignment, user, cfg)
+	}
 
-	default:
-		return fmt.Errorf("please enter a valid assigned-to option")
+	if err != nil {
+		return err
 	}
 
-	// Set the limit on incidents fetched
-	utils.InfoLogger.Printf("Incidents limit set to: %d", constants.IncidentsLimit)
-	incidentOpts.Limit = constants.IncidentsLimit
+	utils.InfoLogger.Printf("Incidents urgency set to: %s", strings.Join(incidentOpts.Urgencies, ", "))
+	utils.InfoLogger.Printf("Retrieving incidents assigned to: %s", tui.AssignedTo)
+	utils.InfoLogger.Printf("Retrieving incidents with status: %s", strings.Join(incidentOpts.Statuses, ", "))
 
 	// Fetch incidents
 	utils.InfoLogger.Printf("GET: fetching incidents")
-	incidents, err := pdcli.GetIncidents(client, &incidentOpts)
+	incidents, hidden, err := pdcli.GetAllIncidents(client, &incidentOpts)
 
 	if err != nil {
 		return err
 	}
 
+	tui.HiddenIncidents = len(hidden)
+	utils.InfoLogger.Printf("Suppressed incidents: %d", len(hidden))
+
 	// Get incident alerts
 	utils.InfoLogger.Printf("GET: fetching incident alerts")
 	for _, incident := range incidents {
@@ -231,11 +300,25 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 		alerts = append(alerts, incidentAlerts...)
 	}
 
+	alerts, err = pdcli.FilterAlerts(alerts, tui.Filters)
+
+	if err != nil {
+		return err
+	}
+
+	err = pdcli.SortAlerts(alerts, tui.SortBy)
+
+	if err != nil {
+		return err
+	}
+
 	tui.Alerts = alerts
 	tui.IncidentOpts = incidentOpts
 
 	tui.InitAlertsUI(tui.Alerts, ui.AlertsTableTitle, ui.AlertsPageTitle)
 	tui.InitAlertsSecondaryView()
+	tui.StartAutoRefresh(cfg.Refresh())
+
 	// Start TUI
 	err = tui.StartApp()
 
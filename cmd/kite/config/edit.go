@@ -0,0 +1,124 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// defaultEditor edits the configuration file when neither VISUAL nor EDITOR are set
+const defaultEditor = "vi"
+
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "This command opens the configuration file in an editor.",
+	Long: "Running the kite config edit command opens a copy of the configuration file in the editor of the VISUAL or EDITOR environment variable, vi by default. " +
+		"The edited configuration is validated before it replaces the configuration file, it is kept in a temporary file if it is invalid.",
+	Args: cobra.NoArgs,
+	RunE: editHandler,
+}
+
+func editHandler(cmd *cobra.Command, args []string) error {
+	path, err := kiteConfig.Find()
+
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "kite-config-*.json")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	tmp.Close()
+
+	if err != nil {
+		return err
+	}
+
+	err = runEditor(tmp.Name())
+
+	if err != nil {
+		return err
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(edited, data) {
+		os.Remove(tmp.Name())
+		fmt.Println("Edit cancelled, no changes made")
+		return nil
+	}
+
+	profiles, err := kiteConfig.Validate(edited)
+
+	for name := range profiles {
+		if err == nil {
+			err = validateColumns(profiles[name])
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("%v\nThe edited configuration is kept in %s", err, tmp.Name())
+	}
+
+	err = os.WriteFile(path, edited, 0600)
+
+	if err != nil {
+		return fmt.Errorf("cannot save configuration file '%s': %v", path, err)
+	}
+
+	os.Remove(tmp.Name())
+	fmt.Println("Configuration file saved")
+
+	return nil
+}
+
+// runEditor edits the file with the editor of the environment, it can have arguments e.g. 'code --wait'.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	args := append(strings.Fields(editor), path)
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+
+	if err != nil {
+		return fmt.Errorf("editor '%s' failed: %v", editor, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "This command prints the value of a configuration key.",
+	Long: "Running the kite config get command prints the value of a configuration key after applying the flags and the environment variables, or its default. " +
+		"The keys are the ones listed by 'kite config view', the secrets are redacted.",
+	Args: cobra.ExactArgs(1),
+	RunE: getHandler,
+}
+
+func getHandler(cmd *cobra.Command, args []string) error {
+	field, err := kiteConfig.LookupField(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	cfg, err := kiteConfig.Read()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	value := field.Value(cfg)
+
+	if field.Secret && value != "" {
+		value = redacted
+	}
+
+	fmt.Println(value)
+
+	return nil
+}
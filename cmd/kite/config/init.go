@@ -0,0 +1,328 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var initOptions struct {
+	teamID string
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "This command discovers the on-call schedules and Silent Test escalation policies of your team.",
+	Long: "Running the kite config init command lists the on-call schedules of the escalation policies of your team and the Silent Test escalation policies, " +
+		"and prompts you to select the ones used by kite. The selected IDs are saved to the org section of the configuration file.",
+	Args: cobra.NoArgs,
+	RunE: initHandler,
+}
+
+// scheduleRole is an on-call schedule of the org profile.
+type scheduleRole struct {
+	name    string
+	keyword string
+	id      *string
+}
+
+func init() {
+	initCmd.Flags().StringVar(
+		&initOptions.teamID,
+		"team-id",
+		"",
+		"ID of the team whose schedules are discovered, defaults to the team selected with 'kite teams'.",
+	)
+}
+
+func initHandler(cmd *cobra.Command, args []string) error {
+
+	// Load the configuration file
+	cfg, err := kiteConfig.Load()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// PagerDuty API client
+	pdClient, err := client.NewClient(cfg).Connect()
+
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	teamID := initOptions.teamID
+
+	if teamID == "" {
+		teamID, err = selectTeamID(cfg, reader)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	org, err := DiscoverOrg(pdClient, teamID, cfg.Organization(), reader)
+
+	if err != nil {
+		return err
+	}
+
+	cfg.Org = &org
+
+	// Save the modified configuration
+	err = kiteConfig.Save(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Org profile saved successfully")
+
+	return nil
+}
+
+// DiscoverOrg lists the on-call schedules of the escalation policies of the given team and the Silent Test escalation policies.
+// It prompts the user to select the schedule of each on-call role and the Silent Test escalation policies, and returns the resulting org profile.
+// Pressing enter keeps the suggested value, which is the schedule named after the role or the currently configured one.
+func DiscoverOrg(c client.PagerDutyClient, teamID string, current kiteConfig.Org, stdin io.Reader) (kiteConfig.Org, error) {
+	org := current
+	org.TeamID = teamID
+
+	reader := bufio.NewReader(stdin)
+
+	policies, err := listEscalationPolicies(c, pdApi.ListEscalationPoliciesOptions{TeamIDs: []string{teamID}})
+
+	if err != nil {
+		return org, err
+	}
+
+	schedules := scheduleTargets(policies)
+
+	if len(schedules) == 0 {
+		return org, fmt.Errorf("no on-call schedules found for the team %s", teamID)
+	}
+
+	fmt.Println("On-call schedules of the team:")
+
+	for i, schedule := range schedules {
+		fmt.Printf("%d. %s (%s)\n", i+1, schedule.Summary, schedule.ID)
+	}
+
+	roles := []scheduleRole{
+		{name: "Primary", keyword: "primary", id: &org.PrimaryScheduleID},
+		{name: "Secondary", keyword: "secondary", id: &org.SecondaryScheduleID},
+		{name: "Manager", keyword: "manag", id: &org.ManagerScheduleID},
+		{name: "Weekend", keyword: "weekend", id: &org.WeekendScheduleID},
+		{name: "Investigator", keyword: "investigat", id: &org.InvestigatorScheduleID},
+	}
+
+	for _, role := range roles {
+		suggested := suggestSchedule(schedules, role.keyword, *role.id)
+
+		if suggested >= 0 {
+			fmt.Printf("%s schedule [%d]: ", role.name, suggested+1)
+		} else {
+			fmt.Printf("%s schedule [%s]: ", role.name, *role.id)
+		}
+
+		input, err := readLine(reader)
+
+		if err != nil {
+			return org, err
+		}
+
+		if input == "" {
+			if suggested >= 0 {
+				*role.id = schedules[suggested].ID
+			}
+			continue
+		}
+
+		index, err := strconv.Atoi(input)
+
+		if err != nil || index < 1 || index > len(schedules) {
+			return org, fmt.Errorf("please select a valid option")
+		}
+
+		*role.id = schedules[index-1].ID
+	}
+
+	silentPolicies, err := listEscalationPolicies(c, pdApi.ListEscalationPoliciesOptions{Query: "silent"})
+
+	if err != nil {
+		return org, err
+	}
+
+	if len(silentPolicies) == 0 {
+		return org, nil
+	}
+
+	fmt.Println("Silent Test escalation policies:")
+
+	for i, policy := range silentPolicies {
+		fmt.Printf("%d. %s (%s)\n", i+1, policy.Name, policy.ID)
+	}
+
+	fmt.Print("Silent Test escalation policies (e.g. 1,3) [all]: ")
+
+	input, err := readLine(reader)
+
+	if err != nil {
+		return org, err
+	}
+
+	org.SilentTestEscalationPolicyIDs = nil
+
+	if input == "" || strings.EqualFold(input, "all") {
+		for _, policy := range silentPolicies {
+			org.SilentTestEscalationPolicyIDs = append(org.SilentTestEscalationPolicyIDs, policy.ID)
+		}
+
+		return org, nil
+	}
+
+	for _, option := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		index, err := strconv.Atoi(option)
+
+		if err != nil || index < 1 || index > len(silentPolicies) {
+			return org, fmt.Errorf("please select a valid option")
+		}
+
+		org.SilentTestEscalationPolicyIDs = append(org.SilentTestEscalationPolicyIDs, silentPolicies[index-1].ID)
+	}
+
+	return org, nil
+}
+
+// selectTeamID returns the team selected with 'kite teams', the user is prompted to choose one if more than one team is selected.
+func selectTeamID(cfg *kiteConfig.Config, reader *bufio.Reader) (string, error) {
+	switch len(cfg.Teams) {
+	case 0:
+		return cfg.Organization().TeamID, nil
+	case 1:
+		return cfg.Teams[0].ID, nil
+	}
+
+	for i, team := range cfg.Teams {
+		fmt.Printf("%d. %s\n", i+1, team.Name)
+	}
+
+	fmt.Print("Select Team: ")
+
+	input, err := readLine(reader)
+
+	if err != nil {
+		return "", err
+	}
+
+	index, err := strconv.Atoi(input)
+
+	if err != nil || index < 1 || index > len(cfg.Teams) {
+		return "", fmt.Errorf("please select a valid option")
+	}
+
+	return cfg.Teams[index-1].ID, nil
+}
+
+// listEscalationPolicies fetches all the pages of escalation policies matching the given options.
+func listEscalationPolicies(c client.PagerDutyClient, opts pdApi.ListEscalationPoliciesOptions) ([]pdApi.EscalationPolicy, error) {
+	var policies []pdApi.EscalationPolicy
+
+	opts.Limit = 100
+
+	for {
+		response, err := c.ListEscalationPolicies(opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, response.EscalationPolicies...)
+
+		if !response.More {
+			return policies, nil
+		}
+
+		opts.Offset += opts.Limit
+	}
+}
+
+// scheduleTargets returns the schedules targeted by the rules of the given escalation policies.
+func scheduleTargets(policies []pdApi.EscalationPolicy) []pdApi.APIObject {
+	var schedules []pdApi.APIObject
+
+	seen := make(map[string]bool)
+
+	for _, policy := range policies {
+		for _, rule := range policy.EscalationRules {
+			for _, target := range rule.Targets {
+				if !strings.HasPrefix(target.Type, "schedule") || seen[target.ID] {
+					continue
+				}
+
+				seen[target.ID] = true
+				schedules = append(schedules, target)
+			}
+		}
+	}
+
+	return schedules
+}
+
+// suggestSchedule returns the index of the schedule named after the role or of the currently configured schedule, -1 if there is none.
+func suggestSchedule(schedules []pdApi.APIObject, keyword string, currentID string) int {
+	for i, schedule := range schedules {
+		if strings.Contains(strings.ToLower(schedule.Summary), keyword) {
+			return i
+		}
+	}
+
+	for i, schedule := range schedules {
+		if schedule.ID == currentID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// readLine reads a line from the reader and trims the surrounding whitespace.
+func readLine(reader *bufio.Reader) (string, error) {
+	input, err := reader.ReadString('\n')
+
+	if err != nil && !(err == io.EOF && input != "") {
+		return "", err
+	}
+
+	return strings.TrimSpace(input), nil
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secrets"
+	"github.com/spf13/cobra"
+)
+
+var secretsOptions struct {
+	backend            string
+	apiKeyCommand      string
+	accessTokenCommand string
+}
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "This command manages where the API key and the GitHub token are stored.",
+	Args:  cobra.NoArgs,
+}
+
+var migrateSecretsCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "This command moves the API key and the GitHub token to a secret backend.",
+	Long: "Running the kite config secrets migrate command stores the API key and the GitHub token in the system keyring, an encrypted file or reads them from commands, " +
+		"e.g. 'pass show kite/api-key'. Only the references of the secrets are then saved in the configuration file.",
+	Args: cobra.NoArgs,
+	RunE: migrateSecretsHandler,
+}
+
+func init() {
+	migrateSecretsCmd.Flags().StringVar(
+		&secretsOptions.backend,
+		"backend",
+		secrets.KeyringBackend,
+		fmt.Sprintf("Secret backend, one of: %s, %s.", strings.Join(secrets.Backends(), ", "), secrets.PlaintextBackend),
+	)
+
+	migrateSecretsCmd.Flags().StringVar(
+		&secretsOptions.apiKeyCommand,
+		"api-key-command",
+		"",
+		"Command printing the API key, required by the command backend.",
+	)
+
+	migrateSecretsCmd.Flags().StringVar(
+		&secretsOptions.accessTokenCommand,
+		"gh-token-command",
+		"",
+		"Command printing the GitHub token, for the command backend.",
+	)
+
+	secretsCmd.AddCommand(migrateSecretsCmd)
+}
+
+func migrateSecretsHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := kiteConfig.Load()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	previousRefs := []string{cfg.ApiKeyRef, cfg.AccessTokenRef}
+
+	err = cfg.MigrateSecrets(secretsOptions.backend, secretsOptions.apiKeyCommand, secretsOptions.accessTokenCommand)
+
+	if err != nil {
+		return err
+	}
+
+	err = kiteConfig.Save(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	// The secrets are removed from the backend they were previously stored in
+	err = cfg.DeleteSecrets(previousRefs)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The secrets are now stored in the %s backend\n", secretsOptions.backend)
+
+	return nil
+}
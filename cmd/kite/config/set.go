@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/spf13/cobra"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "This command sets the value of a configuration key.",
+	Long: "Running the kite config set command validates the value of a configuration key and saves it to the configuration file, e.g. 'kite config set timezone Europe/Prague'. " +
+		"The column presets and the keybindings are set with the column_presets.<name> and keybindings.<action> keys. The secrets are set with the 'kite login' command.",
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfig(args[0], args[1])
+	},
+}
+
+var unsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "This command removes a configuration key from the configuration file, its default is then used.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfig(args[0], "")
+	},
+}
+
+// updateConfig validates and saves the value of a configuration key, an empty value unsets the key.
+func updateConfig(key string, value string) error {
+	field, err := kiteConfig.LookupField(key)
+
+	if err != nil {
+		return err
+	}
+
+	if field.Secret {
+		return fmt.Errorf("the %s secret can only be set with the 'kite login' command", key)
+	}
+
+	cfg, err := kiteConfig.Read()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = field.Set(cfg, value)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateColumns(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	err = cfg.ValidateKeybindings()
+
+	if err != nil {
+		return err
+	}
+
+	err = kiteConfig.Save(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	if field.Overridden() {
+		fmt.Fprintf(os.Stderr, "The %s key is overridden by %s\n", key, cfg.Origin(key))
+	}
+
+	return nil
+}
+
+// validateColumns validates the columns of the column presets and of the saved views.
+func validateColumns(cfg *kiteConfig.Config) error {
+	for name, columns := range cfg.ColumnPresets {
+		_, err := pdcli.ParseColumns(columns)
+
+		if err != nil {
+			return fmt.Errorf("invalid value of column_presets.%s: %v", name, err)
+		}
+	}
+
+	for _, view := range cfg.Views {
+		if view.Columns == "" {
+			continue
+		}
+
+		_, err := pdcli.ParseColumns(cfg.Columns(view.Columns))
+
+		if err != nil {
+			return fmt.Errorf("invalid columns of view '%s': %v", view.Name, err)
+		}
+	}
+
+	return nil
+}
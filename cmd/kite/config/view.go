@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// redacted replaces the secret values when displayed
+const redacted = "<redacted>"
+
+var viewOptions struct {
+	showOrigin bool
+}
+
+var viewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "This command prints the configuration values.",
+	Long: "Running the kite config view command prints the configuration values after applying the flags and the environment variables, the secrets are redacted. " +
+		"The precedence order is flag, environment variable, configuration file and default.",
+	Args: cobra.NoArgs,
+	RunE: viewHandler,
+}
+
+func init() {
+	viewCmd.Flags().BoolVar(
+		&viewOptions.showOrigin,
+		"show-origin",
+		false,
+		"Print where each value comes from, a flag, an environment variable, the configuration file, a secret backend or the default.",
+	)
+}
+
+func viewHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := kiteConfig.Read()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if viewOptions.showOrigin {
+		fmt.Fprintln(w, "KEY\tVALUE\tORIGIN")
+	} else {
+		fmt.Fprintln(w, "KEY\tVALUE")
+	}
+
+	for _, field := range kiteConfig.ConfigFields(cfg) {
+		value := field.Value(cfg)
+
+		if field.Secret && value != "" {
+			value = redacted
+		}
+
+		if value == "" {
+			value = "N/A"
+		}
+
+		if viewOptions.showOrigin {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", field.Key, value, cfg.Origin(field.Key))
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", field.Key, value)
+		}
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/doctor"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "This command checks the kite setup.",
+	Long: "Running the kite doctor command checks the configuration file permissions, the PagerDuty API key and rate limit, the GitHub token, " +
+		"the ocm-container and ocm executables, the OCM login and the terminal emulator. Each check either passes, warns or fails.",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         doctorHandler,
+}
+
+func doctorHandler(cmd *cobra.Command, args []string) error {
+	checks := doctor.Run()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDETAILS")
+
+	for _, check := range checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, strings.ToUpper(check.Status), check.Message)
+	}
+
+	err := w.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	if doctor.Failed(checks) {
+		return fmt.Errorf("some checks failed")
+	}
+
+	return nil
+}
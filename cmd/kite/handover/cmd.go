@@ -0,0 +1,211 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handover
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	handover "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/handover"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var options struct {
+	format  string
+	output  string
+	since   string
+	until   string
+	preview bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "handover",
+	Short: "This command generates the shift handover report of your team.",
+	Long: "Running the kite handover command compiles the acknowledged and triggered incidents of the teams selected with 'kite teams', " +
+		"with their alert metadata, latest notes and timeline during the shift, and the incoming on-call. The shift defaults to the team layer currently on-call.",
+	Args: cobra.NoArgs,
+	RunE: handoverHandler,
+}
+
+func init() {
+	Cmd.Flags().StringVar(
+		&options.format,
+		"format",
+		handover.MarkdownFormat,
+		"Format of the report, md or json.",
+	)
+
+	Cmd.Flags().StringVarP(
+		&options.output,
+		"output",
+		"o",
+		"-",
+		"Path of the report file, '-' writes to the standard output.",
+	)
+
+	Cmd.Flags().StringVar(
+		&options.since,
+		"since",
+		"",
+		"Start of the shift, RFC3339 or natural language e.g. '12 hours ago' (default start of the current on-call layer).",
+	)
+
+	Cmd.Flags().StringVar(
+		&options.until,
+		"until",
+		"",
+		"End of the shift, RFC3339 or natural language e.g. 'in 2h' (default end of the current on-call layer).",
+	)
+
+	Cmd.Flags().BoolVar(
+		&options.preview,
+		"preview",
+		false,
+		"Preview and edit the report in the terminal UI before saving it.",
+	)
+}
+
+// handoverHandler is the handler for kite handover.
+func handoverHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	err = utils.SetTimeDisplay(cfg.Timezone, cfg.RelativeTimes)
+
+	if err != nil {
+		return err
+	}
+
+	err = pdcli.SetSuppression(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	pdClient, err := client.NewClient(cfg).Connect()
+
+	if err != nil {
+		return err
+	}
+
+	user, err := pdClient.GetCurrentUser(pdApi.GetCurrentUserOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(utils.DisplayLocation)
+
+	since, until, err := shiftWindow(pdClient, cfg, now)
+
+	if err != nil {
+		return err
+	}
+
+	report, err := handover.GenerateReport(pdClient, cfg, user, since, until, now)
+
+	if err != nil {
+		return err
+	}
+
+	rendered, err := handover.Render(report, options.format)
+
+	if err != nil {
+		return err
+	}
+
+	if !options.preview {
+		return save(rendered)
+	}
+
+	var tui ui.TUI
+	var saved bool
+
+	// The edited report is saved once the terminal UI exits
+	tui.Init()
+	tui.InitHandoverUI(rendered, func(edited string) error {
+		rendered, saved = edited, true
+		return nil
+	})
+
+	err = tui.StartApp()
+
+	if err != nil || !saved {
+		return err
+	}
+
+	return save(rendered)
+}
+
+// shiftWindow returns the shift of the report, the current on-call layer of the team unless --since or --until are set.
+func shiftWindow(c client.PagerDutyClient, cfg *config.Config, now time.Time) (time.Time, time.Time, error) {
+	since, until, err := handover.CurrentShift(c, cfg.Organization(), now)
+
+	if err != nil {
+		return since, until, err
+	}
+
+	if options.since != "" {
+		since, err = utils.ParseTime(options.since, now)
+
+		if err != nil {
+			return since, until, err
+		}
+	}
+
+	if options.until != "" {
+		until, err = utils.ParseTime(options.until, now)
+
+		if err != nil {
+			return since, until, err
+		}
+	}
+
+	if !until.After(since) {
+		return since, until, fmt.Errorf("the until time must be after the since time")
+	}
+
+	return since, until, nil
+}
+
+// save writes the report to the output file or the standard output.
+func save(report string) error {
+	if options.output == "-" {
+		fmt.Print(report)
+		return nil
+	}
+
+	err := os.WriteFile(options.output, []byte(report), 0600)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Handover report saved to %s\n", options.output)
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package incident
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	oncall "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+	"github.com/spf13/cobra"
+)
+
+var respondersOptions struct {
+	targets []string
+	message string
+}
+
+var respondersCmd = &cobra.Command{
+	Use:   "responders",
+	Short: "This command manages the responders of an incident.",
+	Args:  cobra.NoArgs,
+}
+
+var respondersAddCmd = &cobra.Command{
+	Use:   "add <incident-id>",
+	Short: "Request additional responders for an incident.",
+	Long: "Running the kite incident responders add command requests users or escalation policies to respond to the given incident. " +
+		"The targets are the users of the team layer currently on-call and the escalation policies of all the teams, you are prompted to select them if --target is not set.",
+	Args: cobra.ExactArgs(1),
+	RunE: respondersAddHandler,
+}
+
+func init() {
+	respondersAddCmd.Flags().StringSliceVar(
+		&respondersOptions.targets,
+		"target",
+		nil,
+		"ID or name of a user or an escalation policy currently on-call, can be repeated.",
+	)
+
+	respondersAddCmd.Flags().StringVar(
+		&respondersOptions.message,
+		"message",
+		pdcli.DefaultResponderMessage,
+		"Message sent to the requested responders.",
+	)
+}
+
+// respondersAddHandler is the handler for kite incident responders add.
+func respondersAddHandler(cmd *cobra.Command, args []string) error {
+	var selected []oncall.ResponderTarget
+
+	incidentID := strings.TrimSpace(args[0])
+
+	if match, _ := regexp.MatchString(constants.IncidentIdRegex, incidentID); !match {
+		return fmt.Errorf("invalid incident ID")
+	}
+
+	cfg, err := config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	pdClient, err := client.NewClient(cfg).Connect()
+
+	if err != nil {
+		return err
+	}
+
+	targets, err := oncall.OncallResponderTargets(pdClient, cfg.Organization(), time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	if len(respondersOptions.targets) == 0 {
+		selected, err = SelectResponderTargets(targets, os.Stdin)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range respondersOptions.targets {
+		target, err := oncall.FindResponderTarget(targets, strings.TrimSpace(t))
+
+		if err != nil {
+			return err
+		}
+
+		selected = append(selected, target)
+	}
+
+	var references []pdApi.APIObject
+
+	for _, target := range selected {
+		references = append(references, target.Reference())
+	}
+
+	_, err = pdcli.RequestResponders(pdClient, incidentID, respondersOptions.message, references)
+
+	if err != nil {
+		return err
+	}
+
+	for _, target := range selected {
+		fmt.Printf("Requested %s to respond to incident %s\n", target.Name, incidentID)
+	}
+
+	return nil
+}
+
+// SelectResponderTargets prompts the user to select one or more of the given responder targets.
+// Multiple targets are selected by separating the options with commas or spaces.
+func SelectResponderTargets(targets []oncall.ResponderTarget, stdin io.Reader) ([]oncall.ResponderTarget, error) {
+	var selected []oncall.ResponderTarget
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("nobody is currently on-call")
+	}
+
+	for i, target := range targets {
+		fmt.Printf("%d. %s (%s)\n", i+1, target.Name, target.Role)
+	}
+
+	fmt.Print("Select Responders (e.g. 1,3): ")
+
+	reader := bufio.NewReader(stdin)
+
+	input, err := reader.ReadString('\n')
+
+	if err != nil && !(err == io.EOF && input != "") {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+
+	for _, option := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' }) {
+		index, err := strconv.Atoi(option)
+
+		if err != nil || index < 1 || index > len(targets) {
+			return nil, fmt.Errorf("please select a valid option")
+		}
+
+		if !seen[index] {
+			seen[index] = true
+			selected = append(selected, targets[index-1])
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("please select at least one responder")
+	}
+
+	return selected, nil
+}
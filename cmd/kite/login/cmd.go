@@ -17,9 +17,9 @@ limitations under the License.
 package login
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -28,19 +28,28 @@ import (
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var loginArgs struct {
 	apiKey      string
+	apiKeyStdin bool
 	accessToken string
+	ghTokenFile string
+	teams       []string
+	noGitHub    bool
 }
 
 var Cmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to the PagerDuty CLI",
 	Long: `The kite login command logs a user into PagerDuty CLI given a valid API key is provided. 
-	You will have to login only once, all the kite commands are then available even if the terminal restarts.`,
+	You will have to login only once, all the kite commands are then available even if the terminal restarts.
+	The API key and the GitHub token are prompted for when the standard input is a terminal, otherwise they are read from the flags, e.g. in provisioning scripts:
+	kite login --api-key-stdin --gh-token-file ~/.config/gh-token --team "Platform SRE" < api-key
+	The exit code is 2 for invalid flags or values, 3 when the credentials are rejected and 4 when a value is missing and can't be prompted for.`,
 	Args: cobra.NoArgs,
 	RunE: loginHandler,
 }
@@ -53,12 +62,36 @@ func init() {
 		"",
 		"Access API key/token generated from "+constants.APIKeyURL+"\nUse this option to overwrite the existing API key.",
 	)
+	Cmd.Flags().BoolVar(
+		&loginArgs.apiKeyStdin,
+		"api-key-stdin",
+		false,
+		"Read the API key from the standard input, it is not exposed in the process list or the shell history.",
+	)
 	Cmd.Flags().StringVar(
 		&loginArgs.accessToken,
 		"access-token",
 		"",
 		"GitHub Personal Access Token generated from "+constants.AccessTokenURL+"\nUse this option to overwrite the existing Access Token.",
 	)
+	Cmd.Flags().StringVar(
+		&loginArgs.ghTokenFile,
+		"gh-token-file",
+		"",
+		"Read the GitHub Personal Access Token from the file.",
+	)
+	Cmd.Flags().StringSliceVar(
+		&loginArgs.teams,
+		"team",
+		nil,
+		"PagerDuty team ID or name, repeat the flag or separate the teams with commas to select several teams.",
+	)
+	Cmd.Flags().BoolVar(
+		&loginArgs.noGitHub,
+		"no-github",
+		false,
+		"Login without a GitHub token, the SOPs are then not available. A stored GitHub token is deleted.",
+	)
 }
 
 // loginHandler handles the login flow into kite.
@@ -67,38 +100,60 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 	var user string
 	var pdClient client.PagerDutyClient
 
-	// load the configuration info
-	cfg, err := config.Load()
+	err := validateFlags()
 
-	// If no config file can be located
-	// Or if the config file has errors
-	// Or if this is the first time a user is trying to login
-	// A new configuration struct is initialized on login
 	if err != nil {
-		cfg = new(config.Config)
+		return err
 	}
 
-	// Set PagerDuty API key from cmd args or ask interactively
-	if loginArgs.apiKey != "" {
-		cfg.ApiKey = loginArgs.apiKey
-	} else {
-		// Create a new API key and store it in the config file
-		err = generateNewKey(cfg)
+	interactive := term.IsTerminal(int(os.Stdin.Fd()))
 
-		if err != nil {
-			return err
-		}
+	// Load the configuration info, the API key is not validated as it is replaced on login
+	cfg, err := config.Read()
+
+	// A new configuration struct is only initialized when this is the first time a user is trying to login,
+	// the other errors are returned so that the settings of the configuration file are not overwritten
+	if os.IsNotExist(err) && !configFileExists() {
+		cfg, err = new(config.Config), nil
 	}
 
-	// Set Github Access key from cmd args or ask interactively
-	if loginArgs.accessToken != "" {
-		cfg.AccessToken = loginArgs.accessToken
-	} else {
-		// Create a new API key and store it in the config file
-		err = generateNewAccessToken(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Set PagerDuty API key from cmd args, the standard input or ask interactively
+	cfg.ApiKey, err = readAPIKey(cfg.ApiKey, interactive)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = config.ValidateKey(cfg.ApiKey)
+
+	if err != nil {
+		return &utils.ExitError{Code: utils.ExitUsage, Err: err}
+	}
+
+	// Set Github Access key from cmd args, a file or ask interactively
+	previousTokenRef := cfg.AccessTokenRef
+
+	cfg.AccessToken, err = readAccessToken(cfg.AccessToken, interactive)
+
+	if err != nil {
+		return err
+	}
+
+	// The teams are selected interactively when they are neither set by the flags nor already selected
+	if len(cfg.Teams) == 0 && len(loginArgs.teams) == 0 && !interactive {
+		return utils.NewExitError(utils.ExitNoInput, "no PagerDuty team selected, please use the --team flag")
+	}
+
+	// Check the GitHub token has access to the SOPs, the other commands don't check it
+	if cfg.AccessToken != "" {
+		_, err = config.ValidateGHToken(cfg.AccessToken)
 
 		if err != nil {
-			return err
+			return utils.NewExitError(utils.ExitAuth, "invalid GitHub token, it can't access the %s/%s repository: %v", constants.SOPRepoOwner, constants.SOPRepo, err)
 		}
 	}
 
@@ -109,8 +164,17 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Delete the GitHub token of the secret backend when logging in without GitHub
+	if loginArgs.noGitHub {
+		err = cfg.DeleteSecrets([]string{previousTokenRef})
+
+		if err != nil {
+			return err
+		}
+	}
+
 	// Connect to PagerDuty API client
-	pdClient, err = client.NewClient().Connect()
+	pdClient, err = client.NewClient(cfg).Connect()
 
 	if err != nil {
 		return err
@@ -126,19 +190,24 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 	// Print login success message
 	successMessage(user)
 
-	// Check if user has selected a team
-	if cfg.TeamID == "" {
-		teamdID, name, err := teams.SelectTeam(pdClient, os.Stdin)
+	// Select the teams of the flags, or check if user has selected a team
+	if len(loginArgs.teams) > 0 {
+		cfg.Teams, err = resolveTeams(pdClient, loginArgs.teams)
 
 		if err != nil {
 			return err
 		}
+	} else if len(cfg.Teams) == 0 {
+		selectedTeams, err := teams.SelectTeams(pdClient, os.Stdin)
 
-		cfg.TeamID = teamdID
-		cfg.Team = name
+		if err != nil {
+			return err
+		}
+
+		cfg.Teams = selectedTeams
 	}
 
-	// Save the Team ID to the config file
+	// Save the teams to the config file
 	err = config.Save(cfg)
 
 	if err != nil {
@@ -148,43 +217,148 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// generateNewKey prompts the user to create a new API key and saves it to the config file.
-func generateNewKey(cfg *config.Config) (err error) {
-	//prompts the user to generate an API Key
-	fmt.Println("In order to login it is mandatory to provide an API key.\nThe recommended way is to generate an API key via: " + constants.APIKeyURL)
+// configFileExists checks whether the configuration file exists, rather than another file read with it, e.g. the secrets file.
+func configFileExists() bool {
+	path, err := config.Find()
 
-	//Takes standard input from the user and stores it in a variable
-	reader := bufio.NewReader(os.Stdin)
+	if err != nil {
+		return false
+	}
 
-	fmt.Print("API Key: ")
+	_, err = os.Stat(path)
 
-	cfg.ApiKey, err = reader.ReadString('\n')
+	return err == nil
+}
 
-	if err != nil {
-		return err
+// validateFlags checks the login flags don't conflict.
+func validateFlags() error {
+	if loginArgs.apiKey != "" && loginArgs.apiKeyStdin {
+		return utils.NewExitError(utils.ExitUsage, "the --api-key and --api-key-stdin flags can't be used together")
+	}
+
+	if loginArgs.accessToken != "" && loginArgs.ghTokenFile != "" {
+		return utils.NewExitError(utils.ExitUsage, "the --access-token and --gh-token-file flags can't be used together")
+	}
+
+	if loginArgs.noGitHub && (loginArgs.accessToken != "" || loginArgs.ghTokenFile != "") {
+		return utils.NewExitError(utils.ExitUsage, "the --no-github flag can't be used with a GitHub token")
 	}
 
 	return nil
 }
 
-// generateNewKey prompts the user to create a new API key and saves it to the config file.
-func generateNewAccessToken(cfg *config.Config) (err error) {
+// readAPIKey returns the API key of the flags or the standard input, or prompts for it.
+// When the standard input is not a terminal, the current API key is kept.
+func readAPIKey(current string, interactive bool) (string, error) {
+	if loginArgs.apiKey != "" {
+		return strings.TrimSpace(loginArgs.apiKey), nil
+	}
+
+	if loginArgs.apiKeyStdin {
+		data, err := io.ReadAll(os.Stdin)
+
+		if err != nil {
+			return "", fmt.Errorf("cannot read the API key from the standard input: %v", err)
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	if !interactive {
+		if current != "" {
+			return current, nil
+		}
+
+		return "", utils.NewExitError(utils.ExitNoInput, "the API key is required, please use the --api-key-stdin flag")
+	}
+
+	// Create a new API key and store it in the config file
+	return generateNewKey()
+}
+
+// readAccessToken returns the GitHub token of the flags or the token file, or prompts for it.
+// When the standard input is not a terminal, the current GitHub token is kept.
+func readAccessToken(current string, interactive bool) (string, error) {
+	if loginArgs.noGitHub {
+		return "", nil
+	}
+
+	if loginArgs.accessToken != "" {
+		return strings.TrimSpace(loginArgs.accessToken), nil
+	}
+
+	if loginArgs.ghTokenFile != "" {
+		data, err := os.ReadFile(loginArgs.ghTokenFile)
+
+		if err != nil {
+			return "", utils.NewExitError(utils.ExitUsage, "cannot read the GitHub token file: %v", err)
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	if !interactive {
+		if current != "" {
+			return current, nil
+		}
+
+		return "", utils.NewExitError(utils.ExitNoInput, "the GitHub token is required, please use the --gh-token-file flag, or --no-github to login without the SOPs")
+	}
+
+	// Create a new GitHub token and store it in the config file
+	return generateNewAccessToken()
+}
+
+// generateNewKey prompts the user to create a new API key, the input is masked.
+func generateNewKey() (string, error) {
 	//prompts the user to generate an API Key
-	fmt.Println("\nIn order to view SOP it is mandatory to provide an GitHub Access Token.\nThe recommended way is to generate a token via: " + constants.AccessTokenURL)
+	fmt.Fprintln(os.Stderr, "In order to login it is mandatory to provide an API key.\nThe recommended way is to generate an API key via: "+constants.APIKeyURL)
 
-	//Takes standard input from the user and stores it in a variable
-	reader := bufio.NewReader(os.Stdin)
+	return promptSecret("API Key: ")
+}
+
+// generateNewAccessToken prompts the user to create a new GitHub token, the input is masked.
+func generateNewAccessToken() (string, error) {
+	//prompts the user to generate a GitHub token
+	fmt.Fprintln(os.Stderr, "\nIn order to view SOP in kite it is required to provide a GitHub Access Token, leave it empty to login without it.\nThe recommended way is to generate a token via: "+constants.AccessTokenURL)
 
-	fmt.Print("GitHub Access Token: ")
+	return promptSecret("GitHub Access Token: ")
+}
 
-	cfg.AccessToken, err = reader.ReadString('\n')
-	cfg.AccessToken = strings.TrimSuffix(cfg.AccessToken, "\n")
+// promptSecret reads a secret from the terminal without echoing it.
+func promptSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return strings.TrimSpace(string(secret)), nil
+}
+
+// resolveTeams returns the teams of the current user matching the given team IDs or names.
+func resolveTeams(c client.PagerDutyClient, names []string) ([]config.Team, error) {
+	var selectedTeams []config.Team
+
+	user, err := c.GetCurrentUser(pagerduty.GetCurrentUserOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		team, err := teams.ResolveTeam(user, strings.TrimSpace(name))
+
+		if err != nil {
+			return nil, &utils.ExitError{Code: utils.ExitUsage, Err: err}
+		}
+
+		selectedTeams = append(selectedTeams, team)
+	}
+
+	return selectedTeams, nil
 }
 
 // Login handles PagerDuty REST API authentication via an user API token.
@@ -200,7 +374,7 @@ func Login(apiKey string, client client.PagerDutyClient) (string, error) {
 		//`401 Unauthorized` error response
 		if errors.As(err, &apiError) {
 			err = fmt.Errorf("login failed\n%v Unauthorized", apiError.StatusCode)
-			return "", err
+			return "", &utils.ExitError{Code: utils.ExitAuth, Err: err}
 		}
 
 		return "", err
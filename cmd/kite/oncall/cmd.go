@@ -15,16 +15,27 @@ package oncall
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
+var options struct {
+	at      string
+	since   string
+	until   string
+	service string
+}
+
 var Cmd = &cobra.Command{
 	Use:   "oncall",
 	Short: "oncall to the PagerDuty CLI",
@@ -33,24 +44,81 @@ var Cmd = &cobra.Command{
 	RunE:  oncallHandler,
 }
 
+func init() {
+	Cmd.Flags().StringVar(
+		&options.at,
+		"at",
+		"",
+		"View the on-call layer at the given time, RFC3339 or natural language e.g. 'saturday 03:00', 'tomorrow 9am' or 'in 3h'.",
+	)
+
+	Cmd.Flags().StringVar(
+		&options.since,
+		"since",
+		"",
+		"Start of the on-call window, RFC3339 or natural language (default now).",
+	)
+
+	Cmd.Flags().StringVar(
+		&options.until,
+		"until",
+		"",
+		"End of the on-call window, RFC3339 or natural language e.g. 'in 7 days' (default 24 hours after the start).",
+	)
+
+	Cmd.Flags().StringVar(
+		&options.service,
+		"service",
+		"",
+		"Display the current on-call chain of the escalation policy of the given service ID or name.",
+	)
+}
+
 // oncallHandler is the main handler for kite oncall.
 func oncallHandler(cmd *cobra.Command, args []string) (err error) {
 	var (
 		onCallLayers   []pdcli.OncallLayer
 		allTeamsOncall []pdcli.OncallUser
 		nextOncall     []pdcli.OncallUser
-		primary        string
-		secondary      string
 		tui            ui.TUI
 	)
 
+	// Load the configuration file
+	cfg, err := config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	// Set the timestamps display
+	err = utils.SetTimeDisplay(cfg.Timezone, cfg.RelativeTimes)
+
+	if err != nil {
+		return err
+	}
+
+	if options.service != "" {
+		if options.at != "" || options.since != "" || options.until != "" {
+			return fmt.Errorf("the service option cannot be used with the at, since and until options")
+		}
+
+		return serviceOncall(cfg, options.service)
+	}
+
+	// Validate the on-call window before doing any API calls, the times are in the display timezone
+	since, until, at, err := pdcli.OncallWindow(options.at, options.since, options.until, time.Now().In(utils.DisplayLocation))
+
+	if err != nil {
+		return err
+	}
+
 	// Initialize TUI
 	tui.Init()
 	utils.InfoLogger.Print("Initialized terminal UI")
 
 	// Establish a secure connection with the PagerDuty API
 	utils.InfoLogger.Print("Connecting to PagerDuty API")
-	client, err := client.NewClient().Connect()
+	client, err := client.NewClient(cfg).Connect()
 
 	if err != nil {
 		return err
@@ -67,22 +135,24 @@ func oncallHandler(cmd *cobra.Command, args []string) (err error) {
 
 	tui.Username = user.Name
 
-	// Fetch oncall data from Platform-SRE team
-	utils.InfoLogger.Print("GET: fetching on-call data of current user team")
-	onCallLayers, err = pdcli.TeamSREOnCall(client)
+	org := cfg.Organization()
+
+	tui.Client = client
+	tui.Config = cfg
+	tui.OncallSince = since
+	tui.OncallUntil = until
+	tui.OncallAt = at
+
+	// Fetch oncall data from the schedules of the organization
+	utils.InfoLogger.Printf("GET: fetching on-call data of current user team from %s to %s", since.Format(time.RFC3339), until.Format(time.RFC3339))
+	onCallLayers, err = pdcli.TeamSREOnCall(client, org, since, until)
+
 	if err != nil {
 		return err
 	}
 
-	for _, v := range onCallLayers[2].Users {
-		if strings.Contains(v.OncallRole, "Primary") {
-			primary = v.Name
-		}
-
-		if strings.Contains(v.OncallRole, "Secondary") {
-			secondary = v.Name
-		}
-	}
+	current := pdcli.CurrentLayerIndex(onCallLayers, at)
+	primary, secondary := ui.OncallRoles(onCallLayers[current], org.PrimaryScheduleID, org.SecondaryScheduleID)
 
 	// Fetch oncall data from all teams
 	utils.InfoLogger.Print("GET: fetching on-call data of all teams")
@@ -94,23 +164,20 @@ func oncallHandler(cmd *cobra.Command, args []string) (err error) {
 
 	// Fetch the current user's oncall schedule
 	utils.InfoLogger.Print("GET: fetching next on-call schedule of logged in user")
-	nextOncall, err = pdcli.UserNextOncallSchedule(client, user.ID)
+	nextOncall, err = pdcli.UserNextOncallSchedule(client, user.ID, time.Now())
 
 	if err != nil {
 		return err
 	}
 
 	utils.InfoLogger.Print("Initializing on-call view")
-	for idx, x := range onCallLayers {
-		initOncallUI(&tui, x, idx)
-	}
-	initOnCallFirstPage(&tui)
+	tui.InitOncallUI(onCallLayers, current)
 
 	utils.InfoLogger.Print("Initializing all teams on-call view")
-	initAllTeamsOncallUI(&tui, allTeamsOncall)
+	tui.InitAllTeamsOncallUI(allTeamsOncall)
 
 	utils.InfoLogger.Print("Initializing next on-call schedule view")
-	initNextOncallUI(&tui, nextOncall)
+	tui.InitNextOncallUI(nextOncall)
 
 	utils.InfoLogger.Print("Initializing secondary view")
 	tui.InitOnCallSecondaryView(user.Name, primary, secondary)
@@ -124,76 +191,44 @@ func oncallHandler(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
-// initOncallUI initializes TUI table component.
-// It adds the returned table as a new TUI page view.
-func initOncallUI(tui *ui.TUI, onCallData pdcli.OncallLayer, idx int) {
-	headers, data := getOncallTableData(onCallData.Users)
-	tui.Table = tui.InitTable(headers, data, false, false, fmt.Sprintf("[ %s %s ]", ui.OncallTableTitle, onCallData.LayerId))
-	tui.Pages.AddPage(fmt.Sprintf("%s%d", ui.OncallPageTitle, idx), tui.Table, true, false)
-}
+// serviceOncall prints the current on-call chain of the escalation policy of the given service.
+func serviceOncall(cfg *config.Config, service string) error {
+	pdClient, err := client.NewClient(cfg).Connect()
 
-func initOnCallFirstPage(tui *ui.TUI) {
-	tui.CurrentOnCallPage = 2
-	tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", ui.OncallPageTitle, tui.CurrentOnCallPage))
-}
+	if err != nil {
+		return err
+	}
 
-// initOncallUI initializes TUI NextOncall table component.
-// It adds the returned table as a new TUI page view.
-func initNextOncallUI(tui *ui.TUI, onCallData []pdcli.OncallUser) {
-	headers, data := getOncallTableData(onCallData)
-	tui.NextOncallTable = tui.InitTable(headers, data, false, false, ui.NextOncallTableTitle)
-	tui.Pages.AddPage(ui.NextOncallPageTitle, tui.NextOncallTable, true, false)
-}
+	chain, err := pdcli.ServiceEscalationChain(pdClient, service)
 
-// initOncallUI initializes TUI AllTeamsOncall table component.
-// It adds the returned table as a new TUI page view.
-func initAllTeamsOncallUI(tui *ui.TUI, onCallData []pdcli.OncallUser) {
-	headers, data := getOncallTableData(onCallData)
-	tui.AllTeamsOncallTable = tui.InitTable(headers, data, false, false, ui.AllTeamsOncallTableTitle)
-	tui.Pages.AddPage(ui.AllTeamsOncallPageTitle, tui.AllTeamsOncallTable, true, false)
-}
+	if err != nil {
+		return err
+	}
 
-// getOncallTableData parses and returns tabular data for the given oncall data, i.e table headers and rows.
-func getOncallTableData(oncallData []pdcli.OncallUser) ([]string, [][]string) {
-	var tableData [][]string
+	fmt.Printf("Service: %s (%s)\nEscalation policy: %s (%s)\n\n", chain.Service, chain.ServiceID, chain.EscalationPolicy, chain.EscalationPolicyID)
 
-	for _, v := range oncallData {
-		var data []string
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-		if v.EscalationPolicy != "" {
-			data = append(data, v.EscalationPolicy)
-		} else {
-			data = append(data, "N/A")
-		}
+	fmt.Fprintln(w, "LEVEL\tTARGETS\tON-CALL\tUNTIL")
 
-		if v.Name != "" {
-			data = append(data, v.Name)
-		} else {
-			data = append(data, "N/A")
-		}
+	for _, level := range chain.Levels {
+		targets := strings.Join(level.Targets, ", ")
 
-		if v.OncallRole != "" {
-			data = append(data, v.OncallRole)
-		} else {
-			data = append(data, "N/A")
+		if len(level.Users) == 0 {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", level.Level, targets, "N/A", "N/A")
+			continue
 		}
 
-		if v.Start != "" {
-			data = append(data, v.Start)
-		} else {
-			data = append(data, "N/A")
-		}
+		for _, user := range level.Users {
+			until := "N/A"
 
-		if v.End != "" {
-			data = append(data, v.End)
-		} else {
-			data = append(data, "N/A")
-		}
+			if user.End != "" {
+				until = utils.DisplayTimestamp(user.End)
+			}
 
-		tableData = append(tableData, data)
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", level.Level, targets, user.Name, until)
+		}
 	}
 
-	headers := []string{"Escalation Policy", "Name", "Oncall Role", "From", "To"}
-
-	return headers, tableData
+	return w.Flush()
 }
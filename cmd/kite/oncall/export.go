@@ -0,0 +1,137 @@
+package oncall
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+	"github.com/spf13/cobra"
+)
+
+// ICSPath is the path the iCalendar is served at.
+const ICSPath = "/oncall.ics"
+
+var exportOptions struct {
+	ics    bool
+	output string
+	serve  string
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "This command exports your on-call shifts of the next 90 days.",
+	Long: "Running the kite oncall export --ics command writes your on-call shifts of the next 90 days to an iCalendar (.ics) file, " +
+		"or serves it from a local HTTP endpoint which calendar applications can subscribe to.",
+	Args: cobra.NoArgs,
+	RunE: exportHandler,
+}
+
+func init() {
+	exportCmd.Flags().BoolVar(
+		&exportOptions.ics,
+		"ics",
+		false,
+		"Export the on-call shifts in the iCalendar format.",
+	)
+
+	exportCmd.Flags().StringVarP(
+		&exportOptions.output,
+		"output",
+		"o",
+		"oncall.ics",
+		"Path of the exported file, '-' writes to the standard output.",
+	)
+
+	exportCmd.Flags().StringVar(
+		&exportOptions.serve,
+		"serve",
+		"",
+		"Serve the iCalendar at "+ICSPath+" on the given address instead of writing a file, e.g. localhost:8765.",
+	)
+
+	Cmd.AddCommand(exportCmd)
+}
+
+// exportHandler is the handler for kite oncall export.
+func exportHandler(cmd *cobra.Command, args []string) error {
+	if !exportOptions.ics {
+		return fmt.Errorf("please specify the export format, only --ics is supported")
+	}
+
+	// Establish a secure connection with the PagerDuty API
+	pdClient, err := client.NewClient(nil).Connect()
+
+	if err != nil {
+		return err
+	}
+
+	// Fetch the currently logged in user's ID.
+	user, err := pdClient.GetCurrentUser(pagerduty.GetCurrentUserOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	if exportOptions.serve != "" {
+		return serveICS(pdClient, user.ID, exportOptions.serve)
+	}
+
+	calendar, err := userICS(pdClient, user.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if exportOptions.output == "-" {
+		fmt.Print(calendar)
+		return nil
+	}
+
+	err = os.WriteFile(exportOptions.output, []byte(calendar), 0600)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("On-call shifts exported to %s\n", exportOptions.output)
+
+	return nil
+}
+
+// userICS returns the next on-call shifts of the given user as an iCalendar.
+func userICS(c client.PagerDutyClient, userID string) (string, error) {
+	now := time.Now()
+	shifts, err := pdcli.UserNextOncallSchedule(c, userID, now)
+
+	if err != nil {
+		return "", err
+	}
+
+	return pdcli.ICS(shifts, now), nil
+}
+
+// serveICS serves the next on-call shifts of the given user as an iCalendar on the given address.
+// The shifts are fetched on every request so that subscribed calendars stay up to date.
+func serveICS(c client.PagerDutyClient, userID string, addr string) error {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(ICSPath, func(w http.ResponseWriter, r *http.Request) {
+		calendar, err := userICS(c, userID)
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		fmt.Fprint(w, calendar)
+	})
+
+	fmt.Printf("Serving on-call shifts at http://%s%s, press Ctrl+C to stop\n", addr, ICSPath)
+
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,283 @@
+package oncall
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// overrideListPeriod is how far ahead overrides are listed by default.
+const overrideListPeriod = "in 30 days"
+
+var overrideOptions struct {
+	schedule string
+	user     string
+	start    string
+	end      string
+	until    string
+	force    bool
+}
+
+var overrideCmd = &cobra.Command{
+	Use:   "override",
+	Short: "This command manages the overrides of the on-call schedules.",
+	Long: "The kite oncall override commands create, list and delete overrides of the on-call schedules configured in the org profile. " +
+		"Schedules are selected by role (primary, secondary, manager, weekend, investigator) or ID.",
+}
+
+var overrideCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Override an on-call schedule with a user for a time range.",
+	Args:  cobra.NoArgs,
+	RunE:  overrideCreateHandler,
+}
+
+var overrideListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the upcoming overrides of the on-call schedules.",
+	Args:  cobra.NoArgs,
+	RunE:  overrideListHandler,
+}
+
+var overrideDeleteCmd = &cobra.Command{
+	Use:   "delete <override-id>",
+	Short: "Delete an override of an on-call schedule.",
+	Long: "Running the kite oncall override delete command deletes the given override. " +
+		"The override is not deleted if the schedule has nobody on-call during its time range, unless --force is set.",
+	Args: cobra.ExactArgs(1),
+	RunE: overrideDeleteHandler,
+}
+
+func init() {
+	overrideCmd.PersistentFlags().StringVar(
+		&overrideOptions.schedule,
+		"schedule",
+		"",
+		"Role or ID of the overridden schedule, e.g. primary.",
+	)
+
+	overrideCreateCmd.Flags().StringVar(
+		&overrideOptions.user,
+		"user",
+		"",
+		"ID or email of the user on-call during the override (default the current user).",
+	)
+
+	overrideCreateCmd.Flags().StringVar(
+		&overrideOptions.start,
+		"start",
+		"",
+		"Start of the override, RFC3339 or natural language e.g. 'saturday 03:00'.",
+	)
+
+	overrideCreateCmd.Flags().StringVar(
+		&overrideOptions.end,
+		"end",
+		"",
+		"End of the override, RFC3339 or natural language e.g. 'sunday 03:00'.",
+	)
+
+	overrideListCmd.Flags().StringVar(
+		&overrideOptions.until,
+		"until",
+		overrideListPeriod,
+		"List the overrides starting before the given time.",
+	)
+
+	overrideDeleteCmd.Flags().BoolVar(
+		&overrideOptions.force,
+		"force",
+		false,
+		"Delete the override even if it leaves nobody on-call.",
+	)
+
+	_ = overrideCreateCmd.MarkFlagRequired("schedule")
+	_ = overrideCreateCmd.MarkFlagRequired("start")
+	_ = overrideCreateCmd.MarkFlagRequired("end")
+
+	overrideCmd.AddCommand(overrideCreateCmd, overrideListCmd, overrideDeleteCmd)
+	Cmd.AddCommand(overrideCmd)
+}
+
+// overrideCreateHandler is the handler for kite oncall override create.
+func overrideCreateHandler(cmd *cobra.Command, args []string) error {
+	cfg, pdClient, err := overrideSetup()
+
+	if err != nil {
+		return err
+	}
+
+	scheduleID, err := pdcli.ResolveSchedule(cfg.Organization(), overrideOptions.schedule)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(utils.DisplayLocation)
+
+	start, err := utils.ParseTime(overrideOptions.start, now)
+
+	if err != nil {
+		return err
+	}
+
+	end, err := utils.ParseTime(overrideOptions.end, now)
+
+	if err != nil {
+		return err
+	}
+
+	userID := overrideOptions.user
+
+	if userID == "" {
+		user, err := pdClient.GetCurrentUser(pagerduty.GetCurrentUserOptions{})
+
+		if err != nil {
+			return err
+		}
+
+		userID = user.ID
+	}
+
+	userID, err = pdcli.ResolveUser(pdClient, userID)
+
+	if err != nil {
+		return err
+	}
+
+	override, err := pdcli.CreateOverride(pdClient, scheduleID, userID, start, end)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Override %s created: %s on-call from %s to %s\n",
+		override.ID, override.User, utils.DisplayTimestamp(override.Start), utils.DisplayTimestamp(override.End))
+
+	return nil
+}
+
+// overrideListHandler is the handler for kite oncall override list.
+func overrideListHandler(cmd *cobra.Command, args []string) error {
+	cfg, pdClient, err := overrideSetup()
+
+	if err != nil {
+		return err
+	}
+
+	scheduleIDs, err := overrideSchedules(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(utils.DisplayLocation)
+
+	until, err := utils.ParseTime(overrideOptions.until, now)
+
+	if err != nil {
+		return err
+	}
+
+	overrides, err := pdcli.ListOverrides(pdClient, cfg.Organization(), scheduleIDs, now, until)
+
+	if err != nil {
+		return err
+	}
+
+	if len(overrides) == 0 {
+		fmt.Println("No upcoming overrides")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tSCHEDULE\tUSER\tSTART\tEND")
+
+	for _, o := range overrides {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", o.ID, o.Schedule, o.User, utils.DisplayTimestamp(o.Start), utils.DisplayTimestamp(o.End))
+	}
+
+	return w.Flush()
+}
+
+// overrideDeleteHandler is the handler for kite oncall override delete.
+func overrideDeleteHandler(cmd *cobra.Command, args []string) error {
+	cfg, pdClient, err := overrideSetup()
+
+	if err != nil {
+		return err
+	}
+
+	scheduleIDs, err := overrideSchedules(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	override, err := pdcli.FindOverride(pdClient, cfg.Organization(), scheduleIDs, args[0], now, now.AddDate(1, 0, 0))
+
+	if err != nil {
+		return err
+	}
+
+	err = pdcli.DeleteOverride(pdClient, override, overrideOptions.force)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Override %s deleted\n", override.ID)
+
+	return nil
+}
+
+// overrideSetup loads the configuration and connects to the PagerDuty API.
+func overrideSetup() (*config.Config, client.PagerDutyClient, error) {
+	cfg, err := config.Load()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = utils.SetTimeDisplay(cfg.Timezone, cfg.RelativeTimes)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pdClient, err := client.NewClient(cfg).Connect()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, pdClient, nil
+}
+
+// overrideSchedules returns the schedule selected with --schedule, or all the configured schedules.
+func overrideSchedules(cfg *config.Config) ([]string, error) {
+	org := cfg.Organization()
+
+	if overrideOptions.schedule == "" {
+		return org.ScheduleIDs(), nil
+	}
+
+	scheduleID, err := pdcli.ResolveSchedule(org, overrideOptions.schedule)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{scheduleID}, nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "profile",
+	Short: "This command manages the profiles of the kite configuration file.",
+	Long: "Profiles hold the configuration of several PagerDuty accounts, e.g. a production and a stage account, in the same configuration file. " +
+		"A profile is created with 'kite login --profile <name>' and selected with the --profile flag or the KITE_PROFILE environment variable.",
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(useCmd)
+	Cmd.AddCommand(deleteCmd)
+}
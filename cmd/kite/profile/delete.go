@@ -0,0 +1,43 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <profile>",
+	Short: "This command deletes a profile from the configuration file, with the secrets it stored in a secret backend.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  deleteHandler,
+}
+
+func deleteHandler(cmd *cobra.Command, args []string) error {
+	err := kiteConfig.DeleteProfile(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted profile '%s'\n", args[0])
+
+	return nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "This command lists the profiles of the configuration file, the active profile is marked with '*'.",
+	Args:  cobra.NoArgs,
+	RunE:  listHandler,
+}
+
+func listHandler(cmd *cobra.Command, args []string) error {
+	profiles, active, err := kiteConfig.Profiles()
+
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		return fmt.Errorf("no profiles found, run the 'kite login' command")
+	}
+
+	for _, name := range profiles {
+		if name == active {
+			fmt.Printf("* %s\n", name)
+		} else {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var useCmd = &cobra.Command{
+	Use:   "use <profile>",
+	Short: "This command sets the profile used when neither the --profile flag nor KITE_PROFILE are set.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  useHandler,
+}
+
+func useHandler(cmd *cobra.Command, args []string) error {
+	err := kiteConfig.UseProfile(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Switched to profile '%s'\n", args[0])
+
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	report "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/report"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var options struct {
+	format string
+	output string
+	since  string
+	until  string
+	top    int
+}
+
+var Cmd = &cobra.Command{
+	Use:   "report",
+	Short: "This command reports the incident analytics of your team.",
+	Long: "Running the kite report command fetches the incidents of the teams selected with 'kite teams' created in a date range, " +
+		"and computes the MTTA and MTTR percentiles, the incident counts by alert name, cluster and hour of the day, and who acknowledged them.",
+	Args: cobra.NoArgs,
+	RunE: reportHandler,
+}
+
+func init() {
+	Cmd.Flags().StringVar(
+		&options.since,
+		"since",
+		"7 days ago",
+		"Start of the date range, RFC3339 or natural language e.g. '30 days ago'.",
+	)
+
+	Cmd.Flags().StringVar(
+		&options.until,
+		"until",
+		"",
+		"End of the date range, RFC3339 or natural language e.g. 'yesterday 09:00' (default now).",
+	)
+
+	Cmd.Flags().StringVar(
+		&options.format,
+		"format",
+		report.TableFormat,
+		"Format of the report, table, csv or json.",
+	)
+
+	Cmd.Flags().IntVar(
+		&options.top,
+		"top",
+		10,
+		"Number of alerts, clusters and users listed in the tables, 0 lists them all.",
+	)
+
+	Cmd.Flags().StringVarP(
+		&options.output,
+		"output",
+		"o",
+		"-",
+		"Path of the report file, '-' writes to the standard output.",
+	)
+}
+
+// reportHandler is the handler for kite report.
+func reportHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	err = utils.SetTimeDisplay(cfg.Timezone, cfg.RelativeTimes)
+
+	if err != nil {
+		return err
+	}
+
+	err = pdcli.SetSuppression(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(utils.DisplayLocation)
+
+	since, err := utils.ParseTime(options.since, now)
+
+	if err != nil {
+		return err
+	}
+
+	until := now
+
+	if options.until != "" {
+		until, err = utils.ParseTime(options.until, now)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if !until.After(since) {
+		return fmt.Errorf("the until time must be after the since time")
+	}
+
+	if options.top < 0 {
+		return fmt.Errorf("the number of top entries can't be negative")
+	}
+
+	pdClient, err := client.NewClient(cfg).Connect()
+
+	if err != nil {
+		return err
+	}
+
+	user, err := pdClient.GetCurrentUser(pdApi.GetCurrentUserOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	r, err := report.GenerateReport(pdClient, cfg, user, since, until)
+
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+
+	if options.output != "-" {
+		file, err := os.OpenFile(options.output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		w = file
+	}
+
+	return report.Render(w, r, options.format, options.top)
+}
@@ -16,11 +16,23 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/config"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/doctor"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/handover"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/incident"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/login"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/oncall"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/profile"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/report"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/sop"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/teams"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/terminal"
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 
 	"github.com/spf13/cobra"
 )
@@ -31,12 +43,20 @@ var rootCmd = &cobra.Command{
 	Short:         "An all-in-one incident response tool called kite.",
 	Long:          `It can be used reduce the time taken, from the time, SRE receives a PD alert to the time where troubleshooting on the cluster actually begins. `,
 	SilenceErrors: true,
+
+	PersistentPreRun: setConfigFlags,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The exit code is the one of the error, see utils.ExitCode.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	err := rootCmd.Execute()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(utils.ExitCode(err))
+	}
 }
 
 func init() {
@@ -45,8 +65,51 @@ func init() {
 	rootCmd.AddCommand(oncall.Cmd)
 	rootCmd.AddCommand(teams.Cmd)
 	rootCmd.AddCommand(terminal.Cmd)
+	rootCmd.AddCommand(config.Cmd)
+	rootCmd.AddCommand(incident.Cmd)
+	rootCmd.AddCommand(handover.Cmd)
+	rootCmd.AddCommand(report.Cmd)
+	rootCmd.AddCommand(profile.Cmd)
+	rootCmd.AddCommand(doctor.Cmd)
+	rootCmd.AddCommand(sop.Cmd)
+
+	// The invalid flags of every command exit with the usage exit code
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &utils.ExitError{Code: utils.ExitUsage, Err: err}
+	})
 
 	//Do not provide the default completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
+	// Global flags overriding the configuration file and the environment
+	rootCmd.PersistentFlags().String("config", "", "Path of the configuration file, overrides KITE_CONFIG.")
+
+	for _, field := range kiteConfig.Fields() {
+		if field.Flag == "" {
+			continue
+		}
+
+		rootCmd.PersistentFlags().String(field.Flag, "", fmt.Sprintf("%s Overrides %s.", field.Usage, field.Env))
+
+		if field.Bool {
+			rootCmd.PersistentFlags().Lookup(field.Flag).NoOptDefVal = "true"
+		}
+	}
+}
+
+// setConfigFlags sets the values of the global flags which are set, they take precedence over the configuration file and the environment.
+func setConfigFlags(cmd *cobra.Command, args []string) {
+	values := map[string]string{}
+
+	for _, field := range kiteConfig.Fields() {
+		if field.Flag != "" && cmd.Flags().Changed(field.Flag) {
+			values[field.Key], _ = cmd.Flags().GetString(field.Flag)
+		}
+	}
+
+	kiteConfig.SetFlags(values)
+
+	if path, _ := cmd.Flags().GetString("config"); path != "" {
+		kiteConfig.SetPath(path)
+	}
 }
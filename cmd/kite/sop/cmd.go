@@ -0,0 +1,34 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sop
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "sop",
+	Short: "This command manages the SOPs displayed by kite.",
+	Long: "The SOPs are read from the local clone of the SOP repository set by the sop.local_path configuration key, e.g. 'kite config set sop.local_path ~/git/ops-sop'. " +
+		"They are fetched from GitHub when there is no local clone or the document is not found in it.",
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	Cmd.AddCommand(syncCmd)
+	Cmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,69 @@
+package sop
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
+	"github.com/spf13/cobra"
+)
+
+var searchArgs struct {
+	limit   int
+	refresh bool
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "This command searches the SOPs.",
+	Long: "Running the kite sop search command lists the SOPs matching every word of the query, ranked by the matches in their title, headings, path and body. " +
+		"The SOPs of the local clone set by sop.local_path are searched, otherwise the SOPs are downloaded from GitHub once a day.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: searchHandler,
+}
+
+func init() {
+	searchCmd.Flags().IntVar(&searchArgs.limit, "limit", 10, "Maximum number of results, all of them when 0.")
+	searchCmd.Flags().BoolVar(&searchArgs.refresh, "refresh", false, "Download the SOPs from GitHub again, when there is no local clone.")
+}
+
+func searchHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := kiteConfig.Read()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	index, err := sop.LoadIndex(cfg, searchArgs.refresh)
+
+	if err != nil {
+		return err
+	}
+
+	query := strings.Join(args, " ")
+	results := index.Search(query, searchArgs.limit)
+
+	if len(results) == 0 {
+		fmt.Printf("No SOP matches '%s' in %s\n", query, index.Source)
+		return nil
+	}
+
+	for i, result := range results {
+		fmt.Printf("%d. %s (%s)\n", i+1, result.Title, result.Path)
+		fmt.Printf("   %s\n", result.URL())
+
+		for _, snippet := range result.Snippets {
+			fmt.Printf("   %s\n", snippet)
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package sop
+
+import (
+	"fmt"
+	"os"
+
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "This command pulls the local clone of the SOP repository.",
+	Long:  "Running the kite sop sync command runs 'git pull --ff-only' in the local clone of the SOP repository set by the sop.local_path configuration key.",
+	Args:  cobra.NoArgs,
+	RunE:  syncHandler,
+}
+
+func syncHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := kiteConfig.Read()
+
+	if os.IsNotExist(err) {
+		return fmt.Errorf("configuration file not found, run the 'kite login' command")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	clone := cfg.SOPPath()
+
+	if clone == "" {
+		return fmt.Errorf("no local clone of the SOP repository configured, run 'kite config set sop.local_path <path>'")
+	}
+
+	err = sop.Sync(clone, os.Stdout, os.Stderr)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("SOP repository clone '%s' is up to date\n", clone)
+
+	return nil
+}
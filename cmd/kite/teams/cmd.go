@@ -17,35 +17,35 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "teams",
 	Short: "This command will list all the teams associated with your user account.",
+	Long:  "Running the kite teams command lets you select one or more of your PagerDuty teams, the alerts of all the selected teams are displayed by 'kite alerts --assigned-to team'.",
 	Args:  cobra.NoArgs,
 	RunE:  teamsHandler,
 }
 
 func teamsHandler(cmd *cobra.Command, args []string) error {
 
-	// PagerDuty API client
-	pdClient, err := client.NewClient().Connect()
+	// Load the configuration file
+	cfg, err := config.Load()
 
 	if err != nil {
 		return err
 	}
 
-	// Load the configuration file
-	cfg, err := config.Load()
+	// PagerDuty API client
+	pdClient, err := client.NewClient(cfg).Connect()
 
 	if err != nil {
 		return err
 	}
 
-	// Fetch the user selected team ID
-	teamID, teamName, err := SelectTeam(pdClient, os.Stdin)
+	// Fetch the user selected teams
+	selectedTeams, err := SelectTeams(pdClient, os.Stdin)
 
 	if err != nil {
 		return err
 	}
 
-	cfg.TeamID = teamID
-	cfg.Team = teamName
+	cfg.Teams = selectedTeams
 
 	// Save the modified configuration
 	err = config.Save(cfg)
@@ -54,24 +54,25 @@ func teamsHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Println("PagerDuty team successfully selected.")
+	fmt.Printf("PagerDuty teams successfully selected: %s\n", strings.Join(cfg.TeamNames(), ", "))
 
 	return nil
 }
 
-// SelectTeam prompts the user to select a team and returns the selected team ID and team name.
-func SelectTeam(c client.PagerDutyClient, stdin io.Reader) (string, string, error) {
-	var selectedTeamID string
-	var selectedTeamName string
+// SelectTeams prompts the user to select one or more teams and returns the selected teams.
+// Multiple teams are selected by separating the options with commas or spaces, 'all' selects every team.
+func SelectTeams(c client.PagerDutyClient, stdin io.Reader) ([]config.Team, error) {
+	var selectedTeams []config.Team
 	var userOptions pdApi.GetCurrentUserOptions
 
-	userTeams := make(map[string][]string)
+	userTeams := make(map[string]config.Team)
+	selected := make(map[string]bool)
 
 	// Fetch the currently logged in user details
 	user, err := c.GetCurrentUser(userOptions)
 
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	// Check if the user belongs to any team
@@ -82,28 +83,61 @@ func SelectTeam(c client.PagerDutyClient, stdin io.Reader) (string, string, erro
 
 	for i, team := range user.Teams {
 		index := strconv.Itoa(i + 1)
-		userTeams[index] = []string{team.ID, team.Summary}
+		userTeams[index] = config.Team{ID: team.ID, Name: team.Summary}
 		fmt.Printf("%s. %s\n", index, team.Summary)
 	}
 
-	fmt.Print("Select Team: ")
+	fmt.Print("Select Teams (e.g. 1,3 or all): ")
 
 	reader := bufio.NewReader(stdin)
 
 	input, err := reader.ReadString('\n')
 
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	input = strings.TrimSpace(input)
 
-	if val, ok := userTeams[input]; ok {
-		selectedTeamID = val[0]
-		selectedTeamName = val[1]
-	} else {
-		return "", "", fmt.Errorf("please select a valid option")
+	if strings.EqualFold(input, "all") {
+		for _, team := range user.Teams {
+			selectedTeams = append(selectedTeams, config.Team{ID: team.ID, Name: team.Summary})
+		}
+
+		return selectedTeams, nil
+	}
+
+	options := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	for _, option := range options {
+		team, ok := userTeams[option]
+
+		if !ok {
+			return nil, fmt.Errorf("please select a valid option")
+		}
+
+		if !selected[team.ID] {
+			selected[team.ID] = true
+			selectedTeams = append(selectedTeams, team)
+		}
+	}
+
+	if len(selectedTeams) == 0 {
+		return nil, fmt.Errorf("please select a valid option")
+	}
+
+	return selectedTeams, nil
+}
+
+// ResolveTeam returns the team of the given user matching the given team ID or name.
+func ResolveTeam(user *pdApi.User, team string) (config.Team, error) {
+	for _, t := range user.Teams {
+		if t.ID == team || strings.EqualFold(t.Summary, team) {
+			return config.Team{ID: t.ID, Name: t.Summary}, nil
+		}
 	}
 
-	return selectedTeamID, selectedTeamName, nil
+	return config.Team{}, fmt.Errorf("your user account is not a part of the team '%s'", team)
 }
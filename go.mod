@@ -15,8 +15,10 @@ require (
 	github.com/openshift-online/ocm-sdk-go v0.1.334
 	github.com/rivo/tview v0.0.0-20220916081518-2e69b7385a37
 	github.com/spf13/cobra v1.6.1
+	golang.org/x/crypto v0.7.0
 	golang.org/x/net v0.8.0
 	golang.org/x/oauth2 v0.6.0
+	golang.org/x/term v0.6.0
 )
 
 require (
@@ -53,9 +55,7 @@ require (
 	github.com/prometheus/procfs v0.8.0 // indirect
 	github.com/rivo/uniseg v0.4.2 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/crypto v0.7.0 // indirect
 	golang.org/x/sys v0.6.0 // indirect
-	golang.org/x/term v0.6.0 // indirect
 	golang.org/x/text v0.8.0 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/protobuf v1.28.1 // indirect
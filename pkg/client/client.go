@@ -17,8 +17,13 @@ limitations under the License.
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
@@ -33,16 +38,46 @@ type PagerDutyClient interface {
 	GetService(serviceID string, opts *pdApi.GetServiceOptions) (*pdApi.Service, error)
 	ListOnCalls(opts pdApi.ListOnCallOptions) (*pdApi.ListOnCallsResponse, error)
 	ManageIncidents(from string, incidents []pdApi.ManageIncidentsOptions) (*pdApi.ListIncidentsResponse, error)
+	ListEscalationPolicies(opts pdApi.ListEscalationPoliciesOptions) (*pdApi.ListEscalationPoliciesResponse, error)
+	GetSchedule(scheduleID string, opts pdApi.GetScheduleOptions) (*pdApi.Schedule, error)
+	ListOverrides(scheduleID string, opts pdApi.ListOverridesOptions) (*pdApi.ListOverridesResponse, error)
+	CreateOverride(scheduleID string, override pdApi.Override) (*pdApi.Override, error)
+	DeleteOverride(scheduleID, overrideID string) error
+	ListUsers(opts pdApi.ListUsersOptions) (*pdApi.ListUsersResponse, error)
+	ListServices(opts pdApi.ListServiceOptions) (*pdApi.ListServiceResponse, error)
+	GetEscalationPolicy(policyID string, opts *pdApi.GetEscalationPolicyOptions) (*pdApi.EscalationPolicy, error)
+	ResponderRequest(incidentID string, opts ResponderRequestOptions) (*pdApi.ResponderRequestResponse, error)
+	ListIncidentNotes(incidentID string) ([]pdApi.IncidentNote, error)
+	ListIncidentLogEntries(incidentID string, opts pdApi.ListIncidentLogEntriesOptions) (*pdApi.ListIncidentLogEntriesResponse, error)
+}
+
+// pdAPIEndpoint is the endpoint of the PagerDuty REST API
+const pdAPIEndpoint = "https://api.pagerduty.com"
+
+// ResponderRequestOptions is the body of a responder request.
+// Unlike pdApi.ResponderRequestOptions, the targets are wrapped in responder_request_target objects as the PagerDuty API expects them.
+type ResponderRequestOptions struct {
+	From        string            `json:"-"`
+	Message     string            `json:"message"`
+	RequesterID string            `json:"requester_id"`
+	Targets     []ResponderTarget `json:"responder_request_targets"`
+}
+
+// ResponderTarget is a user or an escalation policy requested to respond to an incident.
+type ResponderTarget struct {
+	Target pdApi.APIObject `json:"responder_request_target"`
 }
 
 type PDClient struct {
 	cfg      *config.Config
-	PdClient PagerDutyClient
+	PdClient *pdApi.Client
 }
 
 // NewClient creates an instance of PDClient that is then used to connect to the actual pagerduty client.
-func NewClient() *PDClient {
-	return &PDClient{}
+// The client uses the given configuration, already loaded by the command, so that its secrets are only resolved once.
+// The configuration is loaded by Connect when it is nil.
+func NewClient(cfg *config.Config) *PDClient {
+	return &PDClient{cfg: cfg}
 }
 
 // Connect uses the information stored in new client to create a new PagerDuty connection.
@@ -53,11 +88,16 @@ func (pd *PDClient) Connect() (client *PDClient, err error) {
 
 		// Load the configuration file
 		pd.cfg, err = config.Load()
-		if err != nil {
+
+		if os.IsNotExist(err) {
 			err = fmt.Errorf("configuration file not found, run the 'kite login' command")
 			return nil, err
 		}
 
+		if err != nil {
+			return nil, err
+		}
+
 		if pd.cfg == nil {
 			err = fmt.Errorf("not logged in, run the 'kite login' command")
 			return nil, err
@@ -67,8 +107,10 @@ func (pd *PDClient) Connect() (client *PDClient, err error) {
 			err = fmt.Errorf("invalid API key, run the 'kite login' command")
 			return nil, err
 		}
+	}
 
-		// Create a new PagerDuty API client
+	// Create a new PagerDuty API client
+	if pd.PdClient == nil {
 		pd.PdClient = pdApi.NewClient(pd.cfg.ApiKey)
 	}
 
@@ -102,3 +144,91 @@ func (c *PDClient) ListOnCalls(opts pdApi.ListOnCallOptions) (*pdApi.ListOnCalls
 func (c *PDClient) ManageIncidents(from string, incidents []pdApi.ManageIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
 	return c.PdClient.ManageIncidents(from, incidents)
 }
+
+func (c *PDClient) ListEscalationPolicies(opts pdApi.ListEscalationPoliciesOptions) (*pdApi.ListEscalationPoliciesResponse, error) {
+	return c.PdClient.ListEscalationPolicies(opts)
+}
+
+func (c *PDClient) GetSchedule(scheduleID string, opts pdApi.GetScheduleOptions) (*pdApi.Schedule, error) {
+	return c.PdClient.GetSchedule(scheduleID, opts)
+}
+
+func (c *PDClient) ListOverrides(scheduleID string, opts pdApi.ListOverridesOptions) (*pdApi.ListOverridesResponse, error) {
+	return c.PdClient.ListOverrides(scheduleID, opts)
+}
+
+func (c *PDClient) CreateOverride(scheduleID string, override pdApi.Override) (*pdApi.Override, error) {
+	return c.PdClient.CreateOverride(scheduleID, override)
+}
+
+func (c *PDClient) DeleteOverride(scheduleID, overrideID string) error {
+	return c.PdClient.DeleteOverride(scheduleID, overrideID)
+}
+
+func (c *PDClient) ListUsers(opts pdApi.ListUsersOptions) (*pdApi.ListUsersResponse, error) {
+	return c.PdClient.ListUsers(opts)
+}
+
+func (c *PDClient) ListServices(opts pdApi.ListServiceOptions) (*pdApi.ListServiceResponse, error) {
+	return c.PdClient.ListServices(opts)
+}
+
+func (c *PDClient) GetEscalationPolicy(policyID string, opts *pdApi.GetEscalationPolicyOptions) (*pdApi.EscalationPolicy, error) {
+	return c.PdClient.GetEscalationPolicy(policyID, opts)
+}
+
+// ResponderRequest posts the responder request itself, go-pagerduty doesn't wrap the targets of the request body.
+func (c *PDClient) ResponderRequest(incidentID string, opts ResponderRequestOptions) (*pdApi.ResponderRequestResponse, error) {
+	body, err := json.Marshal(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, pdAPIEndpoint+"/incidents/"+url.PathEscape(incidentID)+"/responder_requests", bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Token token="+c.cfg.ApiKey)
+	req.Header.Set("From", opts.From)
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Error pdApi.APIErrorObject `json:"error"`
+		}
+
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+
+		return nil, fmt.Errorf("cannot request the responders: %s: %s %s", resp.Status, apiErr.Error.Message, strings.Join(apiErr.Error.Errors, ", "))
+	}
+
+	var response pdApi.ResponderRequestResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *PDClient) ListIncidentNotes(incidentID string) ([]pdApi.IncidentNote, error) {
+	return c.PdClient.ListIncidentNotes(incidentID)
+}
+
+func (c *PDClient) ListIncidentLogEntries(incidentID string, opts pdApi.ListIncidentLogEntriesOptions) (*pdApi.ListIncidentLogEntriesResponse, error) {
+	return c.PdClient.ListIncidentLogEntries(incidentID, opts)
+}
@@ -10,6 +10,7 @@ import (
 
 	pagerduty "github.com/PagerDuty/go-pagerduty"
 	gomock "github.com/golang/mock/gomock"
+	client "github.com/openshift/pagerduty-short-circuiter/pkg/client"
 )
 
 // MockPagerDutyClient is a mock of PagerDutyClient interface.
@@ -35,6 +36,35 @@ func (m *MockPagerDutyClient) EXPECT() *MockPagerDutyClientMockRecorder {
 	return m.recorder
 }
 
+// CreateOverride mocks base method.
+func (m *MockPagerDutyClient) CreateOverride(scheduleID string, override pagerduty.Override) (*pagerduty.Override, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOverride", scheduleID, override)
+	ret0, _ := ret[0].(*pagerduty.Override)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOverride indicates an expected call of CreateOverride.
+func (mr *MockPagerDutyClientMockRecorder) CreateOverride(scheduleID, override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOverride", reflect.TypeOf((*MockPagerDutyClient)(nil).CreateOverride), scheduleID, override)
+}
+
+// DeleteOverride mocks base method.
+func (m *MockPagerDutyClient) DeleteOverride(scheduleID, overrideID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOverride", scheduleID, overrideID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOverride indicates an expected call of DeleteOverride.
+func (mr *MockPagerDutyClientMockRecorder) DeleteOverride(scheduleID, overrideID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOverride", reflect.TypeOf((*MockPagerDutyClient)(nil).DeleteOverride), scheduleID, overrideID)
+}
+
 // GetCurrentUser mocks base method.
 func (m *MockPagerDutyClient) GetCurrentUser(arg0 pagerduty.GetCurrentUserOptions) (*pagerduty.User, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +80,21 @@ func (mr *MockPagerDutyClientMockRecorder) GetCurrentUser(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockPagerDutyClient)(nil).GetCurrentUser), arg0)
 }
 
+// GetEscalationPolicy mocks base method.
+func (m *MockPagerDutyClient) GetEscalationPolicy(policyID string, opts *pagerduty.GetEscalationPolicyOptions) (*pagerduty.EscalationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscalationPolicy", policyID, opts)
+	ret0, _ := ret[0].(*pagerduty.EscalationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscalationPolicy indicates an expected call of GetEscalationPolicy.
+func (mr *MockPagerDutyClientMockRecorder) GetEscalationPolicy(policyID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscalationPolicy", reflect.TypeOf((*MockPagerDutyClient)(nil).GetEscalationPolicy), policyID, opts)
+}
+
 // GetIncidentAlert mocks base method.
 func (m *MockPagerDutyClient) GetIncidentAlert(incidentID, alertID string) (*pagerduty.IncidentAlertResponse, *http.Response, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +111,21 @@ func (mr *MockPagerDutyClientMockRecorder) GetIncidentAlert(incidentID, alertID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentAlert", reflect.TypeOf((*MockPagerDutyClient)(nil).GetIncidentAlert), incidentID, alertID)
 }
 
+// GetSchedule mocks base method.
+func (m *MockPagerDutyClient) GetSchedule(scheduleID string, opts pagerduty.GetScheduleOptions) (*pagerduty.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", scheduleID, opts)
+	ret0, _ := ret[0].(*pagerduty.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockPagerDutyClientMockRecorder) GetSchedule(scheduleID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockPagerDutyClient)(nil).GetSchedule), scheduleID, opts)
+}
+
 // GetService mocks base method.
 func (m *MockPagerDutyClient) GetService(serviceID string, opts *pagerduty.GetServiceOptions) (*pagerduty.Service, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +141,21 @@ func (mr *MockPagerDutyClientMockRecorder) GetService(serviceID, opts interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetService", reflect.TypeOf((*MockPagerDutyClient)(nil).GetService), serviceID, opts)
 }
 
+// ListEscalationPolicies mocks base method.
+func (m *MockPagerDutyClient) ListEscalationPolicies(opts pagerduty.ListEscalationPoliciesOptions) (*pagerduty.ListEscalationPoliciesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEscalationPolicies", opts)
+	ret0, _ := ret[0].(*pagerduty.ListEscalationPoliciesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEscalationPolicies indicates an expected call of ListEscalationPolicies.
+func (mr *MockPagerDutyClientMockRecorder) ListEscalationPolicies(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEscalationPolicies", reflect.TypeOf((*MockPagerDutyClient)(nil).ListEscalationPolicies), opts)
+}
+
 // ListIncidentAlerts mocks base method.
 func (m *MockPagerDutyClient) ListIncidentAlerts(incidentId string) (*pagerduty.ListAlertsResponse, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +171,36 @@ func (mr *MockPagerDutyClientMockRecorder) ListIncidentAlerts(incidentId interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentAlerts", reflect.TypeOf((*MockPagerDutyClient)(nil).ListIncidentAlerts), incidentId)
 }
 
+// ListIncidentLogEntries mocks base method.
+func (m *MockPagerDutyClient) ListIncidentLogEntries(incidentID string, opts pagerduty.ListIncidentLogEntriesOptions) (*pagerduty.ListIncidentLogEntriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentLogEntries", incidentID, opts)
+	ret0, _ := ret[0].(*pagerduty.ListIncidentLogEntriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentLogEntries indicates an expected call of ListIncidentLogEntries.
+func (mr *MockPagerDutyClientMockRecorder) ListIncidentLogEntries(incidentID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentLogEntries", reflect.TypeOf((*MockPagerDutyClient)(nil).ListIncidentLogEntries), incidentID, opts)
+}
+
+// ListIncidentNotes mocks base method.
+func (m *MockPagerDutyClient) ListIncidentNotes(incidentID string) ([]pagerduty.IncidentNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentNotes", incidentID)
+	ret0, _ := ret[0].([]pagerduty.IncidentNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentNotes indicates an expected call of ListIncidentNotes.
+func (mr *MockPagerDutyClientMockRecorder) ListIncidentNotes(incidentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentNotes", reflect.TypeOf((*MockPagerDutyClient)(nil).ListIncidentNotes), incidentID)
+}
+
 // ListIncidents mocks base method.
 func (m *MockPagerDutyClient) ListIncidents(arg0 pagerduty.ListIncidentsOptions) (*pagerduty.ListIncidentsResponse, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +231,51 @@ func (mr *MockPagerDutyClientMockRecorder) ListOnCalls(opts interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOnCalls", reflect.TypeOf((*MockPagerDutyClient)(nil).ListOnCalls), opts)
 }
 
+// ListOverrides mocks base method.
+func (m *MockPagerDutyClient) ListOverrides(scheduleID string, opts pagerduty.ListOverridesOptions) (*pagerduty.ListOverridesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverrides", scheduleID, opts)
+	ret0, _ := ret[0].(*pagerduty.ListOverridesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverrides indicates an expected call of ListOverrides.
+func (mr *MockPagerDutyClientMockRecorder) ListOverrides(scheduleID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverrides", reflect.TypeOf((*MockPagerDutyClient)(nil).ListOverrides), scheduleID, opts)
+}
+
+// ListServices mocks base method.
+func (m *MockPagerDutyClient) ListServices(opts pagerduty.ListServiceOptions) (*pagerduty.ListServiceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServices", opts)
+	ret0, _ := ret[0].(*pagerduty.ListServiceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServices indicates an expected call of ListServices.
+func (mr *MockPagerDutyClientMockRecorder) ListServices(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServices", reflect.TypeOf((*MockPagerDutyClient)(nil).ListServices), opts)
+}
+
+// ListUsers mocks base method.
+func (m *MockPagerDutyClient) ListUsers(opts pagerduty.ListUsersOptions) (*pagerduty.ListUsersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", opts)
+	ret0, _ := ret[0].(*pagerduty.ListUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockPagerDutyClientMockRecorder) ListUsers(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockPagerDutyClient)(nil).ListUsers), opts)
+}
+
 // ManageIncidents mocks base method.
 func (m *MockPagerDutyClient) ManageIncidents(from string, incidents []pagerduty.ManageIncidentsOptions) (*pagerduty.ListIncidentsResponse, error) {
 	m.ctrl.T.Helper()
@@ -140,3 +290,18 @@ func (mr *MockPagerDutyClientMockRecorder) ManageIncidents(from, incidents inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManageIncidents", reflect.TypeOf((*MockPagerDutyClient)(nil).ManageIncidents), from, incidents)
 }
+
+// ResponderRequest mocks base method.
+func (m *MockPagerDutyClient) ResponderRequest(incidentID string, opts client.ResponderRequestOptions) (*pagerduty.ResponderRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResponderRequest", incidentID, opts)
+	ret0, _ := ret[0].(*pagerduty.ResponderRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResponderRequest indicates an expected call of ResponderRequest.
+func (mr *MockPagerDutyClientMockRecorder) ResponderRequest(incidentID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResponderRequest", reflect.TypeOf((*MockPagerDutyClient)(nil).ResponderRequest), incidentID, opts)
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v50/github"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
@@ -34,15 +35,326 @@ import (
 type Config struct {
 	ApiKey      string `json:"api_key,omitempty"`
 	AccessToken string `json:"gh_token,omitempty"`
-	TeamID      string `json:"team_id,omitempty"`
-	Team        string `json:"team,omitempty"`
+	Teams       []Team `json:"teams,omitempty"`
 	Terminal    string `json:"terminal,omitempty"`
+	Views       []View `json:"views,omitempty"`
+
+	// References of the API key and the GitHub token stored in a secret backend, e.g. keyring:pagerduty_api_key
+	ApiKeyRef      string `json:"api_key_ref,omitempty"`
+	AccessTokenRef string `json:"gh_token_ref,omitempty"`
+
+	// SecretBackend stores the API key and the GitHub token, keyring, file or command; they are stored in plaintext when unset
+	SecretBackend string `json:"secret_backend,omitempty"`
+	SecretsFile   string `json:"secrets_file,omitempty"`
+
+	// Timestamps display, the timezone is local, UTC (default) or a named timezone
+	Timezone      string `json:"timezone,omitempty"`
+	RelativeTimes bool   `json:"relative_times,omitempty"`
+
+	// HistoryDays is the number of days of incidents searched for the cluster history
+	HistoryDays int `json:"history_days,omitempty"`
+
+	// RefreshInterval is the interval of the alerts automatic refresh, e.g. 1m; the alerts are only refreshed on demand when unset
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+
+	// ColumnPresets are named lists of alerts columns, usable in place of the columns
+	ColumnPresets map[string]string `json:"column_presets,omitempty"`
+
+	// Keybindings map the TUI actions to the keys replacing their default ones
+	Keybindings map[string]string `json:"keybindings,omitempty"`
+
+	// Suppress overrides the default incidents hidden from the team alerts
+	Suppress *Suppression `json:"suppress,omitempty"`
+
+	// Org overrides the default organization specific PagerDuty IDs
+	Org *Org `json:"org,omitempty"`
+
+	// SOP configures the sources of the SOPs
+	SOP *SOP `json:"sop,omitempty"`
+
+	// Deprecated: single team selection of older config files, it is migrated to Teams on load.
+	TeamID string `json:"team_id,omitempty"`
+	Team   string `json:"team,omitempty"`
+
+	// The secrets resolved from their references and the secrets file passphrase
+	resolved   map[string]string
+	passphrase string
+
+	// The profile of the configuration file in use
+	profile string
+
+	// The path of the configuration file, the origins of its values and the values of the file overridden by the flags or the environment
+	path       string
+	origins    map[string]string
+	overrides  map[string]string
+	fileValues map[string]string
+}
+
+// Team is a PagerDuty team selected by the user.
+type Team struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// View is a named preset of the alerts filters, columns and sort order.
+type View struct {
+	Name       string   `json:"name"`
+	AssignedTo string   `json:"assigned_to,omitempty"`
+	Statuses   []string `json:"statuses,omitempty"`
+	Urgencies  []string `json:"urgencies,omitempty"`
+	Columns    string   `json:"columns,omitempty"`
+	Filters    []string `json:"filters,omitempty"`
+	Sort       string   `json:"sort,omitempty"`
+}
+
+// Suppression lists the incidents hidden from the team alerts.
+// Escalation policies, users and services are matched by ID or name, titles are regular expressions.
+type Suppression struct {
+	EscalationPolicies []string `json:"escalation_policies,omitempty"`
+	Users              []string `json:"users,omitempty"`
+	Services           []string `json:"services,omitempty"`
+	Titles             []string `json:"titles,omitempty"`
+}
+
+// SOP configures the sources of the SOPs, they are read from a local clone of the SOP repository before GitHub.
+type SOP struct {
+	LocalPath string `json:"local_path,omitempty"`
+
+	// MaxAge is the age after which the cached SOPs are validated again with GitHub, e.g. 1h
+	MaxAge string `json:"max_age,omitempty"`
+
+	// Offline only reads the SOPs from the local clone and the cache
+	Offline bool `json:"offline,omitempty"`
+}
+
+// Org lists the organization specific PagerDuty IDs, i.e. the on-call schedules and the Silent Test IDs.
+type Org struct {
+	TeamID                        string   `json:"team_id,omitempty"`
+	PrimaryScheduleID             string   `json:"primary_schedule_id,omitempty"`
+	SecondaryScheduleID           string   `json:"secondary_schedule_id,omitempty"`
+	ManagerScheduleID             string   `json:"manager_schedule_id,omitempty"`
+	WeekendScheduleID             string   `json:"weekend_schedule_id,omitempty"`
+	InvestigatorScheduleID        string   `json:"investigator_schedule_id,omitempty"`
+	SilentTestUserID              string   `json:"silent_test_user_id,omitempty"`
+	NobodyUserID                  string   `json:"nobody_user_id,omitempty"`
+	SilentTestEscalationPolicyIDs []string `json:"silent_test_escalation_policy_ids,omitempty"`
+
+	// LayerNames maps the schedule layer IDs or names to the displayed on-call layer names
+	LayerNames map[string]string `json:"layer_names,omitempty"`
+}
+
+// DefaultOrg returns the organization IDs used when none are configured.
+func DefaultOrg() Org {
+	return Org{
+		TeamID:                 constants.TeamID,
+		PrimaryScheduleID:      constants.PrimaryScheduleID,
+		SecondaryScheduleID:    constants.SecondaryScheduleID,
+		ManagerScheduleID:      constants.OncallManager,
+		WeekendScheduleID:      constants.OncallIDWeekend,
+		InvestigatorScheduleID: constants.InvestigatorID,
+		SilentTestUserID:       constants.SilentTest,
+		NobodyUserID:           constants.NobodySREP,
+		SilentTestEscalationPolicyIDs: []string{
+			constants.SilentTestEscalationPolicyID,
+			constants.CADSilentTestEscalationPolicyID,
+			constants.CADSilentTestStageEscalationPolicyID,
+		},
+	}
+}
+
+// ScheduleIDs returns the IDs of the configured on-call schedules.
+func (o Org) ScheduleIDs() []string {
+	var ids []string
+
+	for _, id := range []string{
+		o.PrimaryScheduleID,
+		o.SecondaryScheduleID,
+		o.ManagerScheduleID,
+		o.WeekendScheduleID,
+		o.InvestigatorScheduleID,
+	} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Suppression returns the suppression which hides the incidents assigned to Silent Test and Nobody.
+func (o Org) Suppression() Suppression {
+	var users []string
+
+	for _, id := range []string{o.SilentTestUserID, o.NobodyUserID} {
+		if id != "" {
+			users = append(users, id)
+		}
+	}
+
+	return Suppression{
+		EscalationPolicies: o.SilentTestEscalationPolicyIDs,
+		Users:              users,
+	}
+}
+
+// Organization returns the configured organization IDs, the IDs which are not configured are set to their defaults.
+func (c *Config) Organization() Org {
+	org := DefaultOrg()
+
+	if c == nil || c.Org == nil {
+		return org
+	}
+
+	if c.Org.TeamID != "" {
+		org.TeamID = c.Org.TeamID
+	}
+
+	if c.Org.PrimaryScheduleID != "" {
+		org.PrimaryScheduleID = c.Org.PrimaryScheduleID
+	}
+
+	if c.Org.SecondaryScheduleID != "" {
+		org.SecondaryScheduleID = c.Org.SecondaryScheduleID
+	}
+
+	if c.Org.ManagerScheduleID != "" {
+		org.ManagerScheduleID = c.Org.ManagerScheduleID
+	}
+
+	if c.Org.WeekendScheduleID != "" {
+		org.WeekendScheduleID = c.Org.WeekendScheduleID
+	}
+
+	if c.Org.InvestigatorScheduleID != "" {
+		org.InvestigatorScheduleID = c.Org.InvestigatorScheduleID
+	}
+
+	if c.Org.SilentTestUserID != "" {
+		org.SilentTestUserID = c.Org.SilentTestUserID
+	}
+
+	if c.Org.NobodyUserID != "" {
+		org.NobodyUserID = c.Org.NobodyUserID
+	}
+
+	if c.Org.SilentTestEscalationPolicyIDs != nil {
+		org.SilentTestEscalationPolicyIDs = c.Org.SilentTestEscalationPolicyIDs
+	}
+
+	org.LayerNames = c.Org.LayerNames
+
+	return org
+}
+
+// DefaultSuppression returns the suppression used when none is configured,
+// it hides the incidents assigned to Silent Test and Nobody.
+func DefaultSuppression() Suppression {
+	return DefaultOrg().Suppression()
+}
+
+// Suppression returns the configured suppression or the one of the organization.
+func (c *Config) Suppression() Suppression {
+	if c == nil || c.Suppress == nil {
+		return c.Organization().Suppression()
+	}
+
+	return *c.Suppress
+}
+
+// TeamIDs returns the IDs of the selected teams.
+func (c *Config) TeamIDs() []string {
+	var ids []string
+
+	for _, team := range c.Teams {
+		ids = append(ids, team.ID)
+	}
+
+	return ids
+}
+
+// TeamNames returns the names of the selected teams.
+func (c *Config) TeamNames() []string {
+	var names []string
+
+	for _, team := range c.Teams {
+		names = append(names, team.Name)
+	}
+
+	return names
+}
+
+// HasGitHub checks whether a GitHub token is configured, the SOPs are fetched from GitHub with it.
+func (c *Config) HasGitHub() bool {
+	return c != nil && c.AccessToken != ""
+}
+
+// SOPPath returns the path of the local clone of the SOP repository, the home directory is expanded. It is empty when unset.
+func (c *Config) SOPPath() string {
+	if c == nil || c.SOP == nil || c.SOP.LocalPath == "" {
+		return ""
+	}
+
+	path := c.SOP.LocalPath
+
+	if home, err := os.UserHomeDir(); err == nil && (path == "~" || strings.HasPrefix(path, "~/")) {
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+
+	return path
+}
+
+// SOPMaxAge returns the age after which the cached SOPs are validated again with GitHub.
+func (c *Config) SOPMaxAge() time.Duration {
+	if c != nil && c.SOP != nil && c.SOP.MaxAge != "" {
+		if maxAge, err := time.ParseDuration(c.SOP.MaxAge); err == nil {
+			return maxAge
+		}
+	}
+
+	return constants.SOPMaxAge * time.Minute
+}
+
+// Offline checks whether the SOPs are only read from the local clone and the cache, without GitHub.
+func (c *Config) Offline() bool {
+	return c != nil && c.SOP != nil && c.SOP.Offline
+}
+
+// HasSOPs checks whether the SOPs can be viewed in kite, read from a local clone, fetched with a GitHub token or read from the cache offline.
+func (c *Config) HasSOPs() bool {
+	return c.HasGitHub() || c.SOPPath() != "" || c.Offline()
+}
+
+// HistoryWindow returns the period of incidents searched for the cluster history.
+func (c *Config) HistoryWindow() time.Duration {
+	days := constants.HistoryDays
+
+	if c != nil && c.HistoryDays > 0 {
+		days = c.HistoryDays
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// View returns the saved view with the given name.
+func (c *Config) View(name string) (*View, error) {
+	for i := range c.Views {
+		if c.Views[i].Name == name {
+			return &c.Views[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("view '%s' not found in the configuration file", name)
 }
 
 // Find returns the pdcli configuration filepath.
 // If the config filepath doesn't exist, the desired config filepath string is created and returned.
 func Find() (string, error) {
 
+	// Return the configuration filepath of the --config flag
+	if configPath != "" {
+		return configPath, nil
+	}
+
 	// Return the test configuration filepath
 	if kiteConfig := os.Getenv("KITE_CONFIG"); kiteConfig != "" {
 		return kiteConfig, nil
@@ -71,23 +383,18 @@ func Save(cfg *Config) error {
 	}
 
 	// Check if the API key is valid
-	cfg.ApiKey, err = validateKey(cfg.ApiKey)
+	cfg.ApiKey, err = ValidateKey(cfg.ApiKey)
 
 	if err != nil {
 		return err
 	}
 
-	// Check if the GitHub key is valid
-	cfg.AccessToken, err = validateGHToken(cfg.AccessToken)
+	cfg.AccessToken = strings.TrimSpace(cfg.AccessToken)
 
-	if err != nil {
-		return err
-	}
-
-	if cfg.TeamID != "" {
+	for i := range cfg.Teams {
 
 		// Check if the team ID is valid
-		cfg.TeamID, err = validateTeamID(cfg.TeamID)
+		cfg.Teams[i].ID, err = validateTeamID(cfg.Teams[i].ID)
 
 		if err != nil {
 			return err
@@ -102,23 +409,59 @@ func Save(cfg *Config) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	// The other profiles of the configuration file are kept
+	profiles, err := readProfilesFile(file)
 
 	if err != nil {
-		return fmt.Errorf("cannot marshal configuration file: %v", err)
+		return err
 	}
 
-	err = os.WriteFile(file, data, 0600)
+	if cfg.profile == "" {
+		cfg.profile = profiles.active()
+	}
+
+	// The overridden values are not saved, neither are the secrets stored in a secret backend
+	stored := cfg.fileConfig()
+
+	err = stored.storeSecrets()
 
 	if err != nil {
-		return fmt.Errorf("cannot save configuration file '%s': %v", file, err)
+		return err
 	}
 
-	return nil
+	cfg.ApiKeyRef, cfg.AccessTokenRef = stored.ApiKeyRef, stored.AccessTokenRef
+	cfg.resolved, cfg.passphrase = stored.resolved, stored.passphrase
+
+	err = profiles.set(cfg.profile, stored)
+
+	if err != nil {
+		return err
+	}
+
+	return profiles.write(file)
 }
 
-// Load loads the configuration file and parses it.
+// Load loads the configuration file, applies the flag and environment overrides and validates the API key format.
+// The credentials are not checked against the APIs, see 'kite doctor'.
 func Load() (config *Config, err error) {
+	config, err = Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = ValidateKey(config.ApiKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Read loads the configuration file and applies the flag and environment overrides, without validating the API key.
+// When the configuration file doesn't exist, the configuration is read from the overrides if the API key is overridden.
+func Read() (config *Config, err error) {
 	//Locate the config filepath
 	configFile, err := Find()
 
@@ -126,50 +469,104 @@ func Load() (config *Config, err error) {
 		return nil, err
 	}
 
+	config = &Config{
+		path:       configFile,
+		origins:    map[string]string{},
+		overrides:  map[string]string{},
+		fileValues: map[string]string{},
+	}
+
 	_, err = os.Stat(configFile)
 
 	if os.IsNotExist(err) {
+		if field, _ := LookupField("api_key"); !field.Overridden() {
+			return nil, err
+		}
+
+		config.profile = (&profilesFile{}).active()
+	} else {
+		err = config.readFile()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = config.applyOverrides(false)
+
+	if err != nil {
 		return nil, err
 	}
 
-	configData, err := os.ReadFile(configFile)
+	err = config.resolveSecrets()
 
 	if err != nil {
-		err = fmt.Errorf("cannot read config file")
 		return nil, err
 	}
 
-	if len(configData) == 0 {
-		err = fmt.Errorf("configuration file is empty")
+	err = config.applyOverrides(true)
+
+	if err != nil {
 		return nil, err
 	}
 
-	config = &Config{}
+	return config, nil
+}
 
-	err = json.Unmarshal(configData, config)
+// readFile parses the profile in use of the configuration file.
+// The configuration file of an older version is migrated and saved.
+func (config *Config) readFile() error {
+	configData, err := os.ReadFile(config.path)
 
 	if err != nil {
-		err = fmt.Errorf("error parsing config file")
-		return nil, err
+		return fmt.Errorf("cannot read config file")
 	}
 
-	_, err = validateKey(config.ApiKey)
+	profiles, migrated, err := parseProfilesFile(configData)
 
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if migrated {
+		err = profiles.write(config.path)
+
+		if err != nil {
+			return err
+		}
+
+		configData, err = json.Marshal(profiles)
+
+		if err != nil {
+			return fmt.Errorf("cannot marshal configuration file: %v", err)
+		}
 	}
 
-	_, err = validateGHToken(config.AccessToken)
+	config.profile = profiles.active()
+
+	profileData, err := profiles.data(config.profile, configData)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return config, nil
+	err = json.Unmarshal(profileData, config)
+
+	if err != nil {
+		return fmt.Errorf("error parsing config file")
+	}
+
+	config.setFileOrigins(profileData)
+
+	if profiles.Profile != "" {
+		config.origins["profile"] = OriginFile + " " + config.path
+	}
+
+	return nil
 }
 
-// validateKey sanitizes and validates the API key string.
-func validateKey(apiKey string) (string, error) {
+// ValidateKey sanitizes and validates the API key string.
+func ValidateKey(apiKey string) (string, error) {
 	apiKey = strings.TrimSpace(apiKey)
 
 	//Compare string with regex
@@ -195,8 +592,9 @@ func validateTeamID(teamID string) (string, error) {
 	return teamID, nil
 }
 
-// Validate Access of GH Token to ops-sop
-func validateGHToken(ghToken string) (string, error) {
+// ValidateGHToken checks the GitHub token has access to the SOP repository.
+// It returns the scopes of classic tokens, fine-grained tokens have none.
+func ValidateGHToken(ghToken string) ([]string, error) {
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: ghToken},
@@ -205,10 +603,19 @@ func validateGHToken(ghToken string) (string, error) {
 
 	client := github.NewClient(tc)
 
-	_, _, err := client.Repositories.Get(ctx, "openshift", "ops-sop")
+	_, resp, err := client.Repositories.Get(ctx, constants.SOPRepoOwner, constants.SOPRepo)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return ghToken, nil
+
+	var scopes []string
+
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
 }
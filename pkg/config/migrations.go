@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ConfigVersion is the version of the configuration file format, the files of older versions are migrated when read.
+const ConfigVersion = 1
+
+// migrations upgrade the profiles of the configuration file, the migration at index i upgrades version i to version i+1.
+var migrations = []func(c *Config){
+	migrateTeams,
+}
+
+// migrate upgrades the profiles of the configuration file to the current version.
+// It returns whether the file was migrated, the files of newer versions can't be read.
+func (f *profilesFile) migrate() (bool, error) {
+	if f.Version > ConfigVersion {
+		return false, fmt.Errorf("the configuration file version %d is not supported by this version of kite, please upgrade kite", f.Version)
+	}
+
+	if f.Version == ConfigVersion {
+		return false, nil
+	}
+
+	for _, migration := range migrations[f.Version:] {
+		migration(f.Config)
+	}
+
+	for name, data := range f.Profiles {
+		profile := &Config{}
+
+		err := json.Unmarshal(data, profile)
+
+		if err != nil {
+			return false, fmt.Errorf("error parsing profile '%s': %v", name, err)
+		}
+
+		for _, migration := range migrations[f.Version:] {
+			migration(profile)
+		}
+
+		err = f.set(name, profile)
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	f.Version = ConfigVersion
+
+	return true, nil
+}
+
+// migrateTeams migrates the single team selection of version 0 to the teams list.
+func migrateTeams(c *Config) {
+	if c.TeamID == "" {
+		return
+	}
+
+	if len(c.Teams) == 0 {
+		c.Teams = []Team{{ID: c.TeamID, Name: c.Team}}
+	}
+
+	c.TeamID = ""
+	c.Team = ""
+}
@@ -0,0 +1,655 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secrets"
+)
+
+// Origins of the configuration values
+const (
+	OriginDefault = "default"
+	OriginFile    = "file"
+	OriginEnv     = "env"
+	OriginFlag    = "flag"
+	OriginSecret  = "secret"
+)
+
+// Field is a configuration file key, it can be overridden by an environment variable and a flag.
+// The precedence order is flag, environment variable, configuration file and default.
+type Field struct {
+	Key   string
+	Env   string
+	Flag  string
+	Usage string
+
+	// Secret values are redacted when displayed, they have no flag so that they don't show in the process list or the shell history
+	Secret bool
+
+	// Bool flags can be set without a value
+	Bool bool
+
+	get func(c *Config) string
+	set func(c *Config, value string) error
+	def func() string
+}
+
+// Sections of the configuration file with keys set by name
+const (
+	columnPresetsKey = "column_presets"
+	keybindingsKey   = "keybindings"
+)
+
+var (
+	// flagValues are the values of the configuration flags by key
+	flagValues = map[string]string{}
+
+	// configPath is the path of the configuration file set by the --config flag
+	configPath string
+)
+
+// Fields returns the configuration fields which can be overridden.
+// The lists of the configuration file, e.g. the views, can only be set in the file.
+func Fields() []Field {
+	return []Field{
+		{
+			Key: "profile", Env: "KITE_PROFILE", Flag: "profile",
+			Usage: "Profile of the configuration file.",
+			get:   func(c *Config) string { return c.profile },
+			set:   func(c *Config, v string) error { c.profile = v; return nil },
+			def:   func() string { return DefaultProfile },
+		},
+		{
+			Key: "api_key", Env: "KITE_PD_API_KEY", Secret: true,
+			get: func(c *Config) string { return c.ApiKey },
+			set: func(c *Config, v string) error { c.ApiKey = v; return nil },
+		},
+		{
+			Key: "gh_token", Env: "KITE_GH_TOKEN", Secret: true,
+			get: func(c *Config) string { return c.AccessToken },
+			set: func(c *Config, v string) error { c.AccessToken = v; return nil },
+		},
+		{
+			Key: "api_key_ref", Env: "KITE_PD_API_KEY_REF",
+			get: func(c *Config) string { return c.ApiKeyRef },
+			set: func(c *Config, v string) error { c.ApiKeyRef = v; return nil },
+		},
+		{
+			Key: "gh_token_ref", Env: "KITE_GH_TOKEN_REF",
+			get: func(c *Config) string { return c.AccessTokenRef },
+			set: func(c *Config, v string) error { c.AccessTokenRef = v; return nil },
+		},
+		{
+			Key: "teams", Env: "KITE_TEAM_ID", Flag: "pd-team-id",
+			Usage: "Comma-separated IDs of the PagerDuty teams.",
+			get:   func(c *Config) string { return strings.Join(c.TeamIDs(), ",") },
+			set:   setTeams,
+		},
+		{
+			Key: "terminal", Env: "KITE_TERMINAL", Flag: "terminal",
+			Usage: "Terminal emulator.",
+			get:   func(c *Config) string { return c.Terminal },
+			set:   func(c *Config, v string) error { c.Terminal = v; return nil },
+		},
+		{
+			Key: "timezone", Env: "KITE_TIMEZONE", Flag: "timezone",
+			Usage: "Timezone of the displayed timestamps, local, UTC or a named timezone.",
+			get:   func(c *Config) string { return c.Timezone },
+			set:   setTimezone,
+			def:   func() string { return "UTC" },
+		},
+		{
+			Key: "relative_times", Env: "KITE_RELATIVE_TIMES", Flag: "relative-times", Bool: true,
+			Usage: "Display the timestamps relative to now.",
+			get:   func(c *Config) string { return strconv.FormatBool(c.RelativeTimes) },
+			set:   setRelativeTimes,
+		},
+		{
+			Key: "history_days", Env: "KITE_HISTORY_DAYS", Flag: "history-days",
+			Usage: "Number of days of incidents searched for the cluster history.",
+			get:   func(c *Config) string { return intValue(c.HistoryDays) },
+			set:   setHistoryDays,
+			def:   func() string { return strconv.Itoa(constants.HistoryDays) },
+		},
+		{
+			Key: "refresh_interval", Env: "KITE_REFRESH_INTERVAL", Flag: "refresh-interval",
+			Usage: "Interval of the alerts automatic refresh, e.g. 1m.",
+			get:   func(c *Config) string { return c.RefreshInterval },
+			set:   setRefreshInterval,
+		},
+		{
+			Key: "secret_backend", Env: "KITE_SECRET_BACKEND", Flag: "secret-backend",
+			Usage: "Secret backend of the API key and the GitHub token, keyring, file or command.",
+			get:   func(c *Config) string { return c.SecretBackend },
+			set:   setSecretBackend,
+			def:   func() string { return secrets.PlaintextBackend },
+		},
+		{
+			Key: "secrets_file", Env: "KITE_SECRETS_FILE", Flag: "secrets-file",
+			Usage: "Path of the encrypted secrets file.",
+			get:   func(c *Config) string { return c.SecretsFile },
+			set:   func(c *Config, v string) error { c.SecretsFile = v; return nil },
+		},
+		{
+			Key: "sop.local_path", Env: "KITE_SOP_LOCAL_PATH",
+			get: func(c *Config) string { return sopValue(c, func(s *SOP) string { return s.LocalPath }) },
+			set: func(c *Config, v string) error {
+				return setSOP(c, func(s *SOP) { s.LocalPath = v })
+			},
+		},
+		{
+			Key: "sop.max_age", Env: "KITE_SOP_MAX_AGE",
+			get: func(c *Config) string { return sopValue(c, func(s *SOP) string { return s.MaxAge }) },
+			set: setSOPMaxAge,
+			def: func() string { return fmt.Sprintf("%dm", constants.SOPMaxAge) },
+		},
+		{
+			Key: "sop.offline", Env: "KITE_OFFLINE", Flag: "offline", Bool: true,
+			Usage: "Read the SOPs from the local clone or the cache only, without GitHub.",
+			get:   func(c *Config) string { return strconv.FormatBool(c.Offline()) },
+			set:   setOffline,
+		},
+		orgField("team_id", func(o *Org) *string { return &o.TeamID }),
+		orgField("primary_schedule_id", func(o *Org) *string { return &o.PrimaryScheduleID }),
+		orgField("secondary_schedule_id", func(o *Org) *string { return &o.SecondaryScheduleID }),
+		orgField("manager_schedule_id", func(o *Org) *string { return &o.ManagerScheduleID }),
+		orgField("weekend_schedule_id", func(o *Org) *string { return &o.WeekendScheduleID }),
+		orgField("investigator_schedule_id", func(o *Org) *string { return &o.InvestigatorScheduleID }),
+		orgField("silent_test_user_id", func(o *Org) *string { return &o.SilentTestUserID }),
+		orgField("nobody_user_id", func(o *Org) *string { return &o.NobodyUserID }),
+		{
+			Key: "org.silent_test_escalation_policy_ids", Env: "KITE_ORG_SILENT_TEST_ESCALATION_POLICY_IDS",
+			get: func(c *Config) string {
+				if c.Org == nil {
+					return ""
+				}
+
+				return strings.Join(c.Org.SilentTestEscalationPolicyIDs, ",")
+			},
+			set: func(c *Config, v string) error {
+				if c.Org == nil {
+					if v == "" {
+						return nil
+					}
+
+					c.Org = &Org{}
+				}
+
+				ids := splitList(v)
+
+				for _, id := range ids {
+					err := validateID(id)
+
+					if err != nil {
+						return err
+					}
+				}
+
+				c.Org.SilentTestEscalationPolicyIDs = ids
+
+				return nil
+			},
+			def: func() string { return strings.Join(DefaultOrg().SilentTestEscalationPolicyIDs, ",") },
+		},
+	}
+}
+
+// ConfigFields returns the configuration fields, with the column presets of the configuration and the keybindings of all the actions.
+// The column presets and the keybindings can only be set in the configuration file.
+func ConfigFields(c *Config) []Field {
+	fields := Fields()
+
+	var presets []string
+
+	for name := range c.ColumnPresets {
+		presets = append(presets, name)
+	}
+
+	sort.Strings(presets)
+
+	for _, name := range presets {
+		fields = append(fields, columnPresetField(name))
+	}
+
+	for _, action := range Actions() {
+		fields = append(fields, keybindingField(action))
+	}
+
+	return fields
+}
+
+// LookupField returns the configuration field with the given key, e.g. timezone, column_presets.<name> or keybindings.<action>.
+func LookupField(key string) (Field, error) {
+	for _, field := range Fields() {
+		if field.Key == key {
+			return field, nil
+		}
+	}
+
+	if name := strings.TrimPrefix(key, columnPresetsKey+"."); name != key && name != "" {
+		return columnPresetField(name), nil
+	}
+
+	if action := strings.TrimPrefix(key, keybindingsKey+"."); action != key {
+		if _, ok := DefaultKeybindings()[action]; ok {
+			return keybindingField(action), nil
+		}
+
+		return Field{}, validateKeybinding(action, "")
+	}
+
+	return Field{}, fmt.Errorf("unknown configuration key '%s'", key)
+}
+
+// Set validates the value and sets it in the configuration, an empty value unsets it.
+func (f Field) Set(c *Config, value string) error {
+	err := f.set(c, value)
+
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %v", f.Key, err)
+	}
+
+	return nil
+}
+
+// Value returns the value of the field in the configuration, or its default.
+func (f Field) Value(c *Config) string {
+	if value := f.get(c); value != "" {
+		return value
+	}
+
+	if f.def != nil {
+		return f.def()
+	}
+
+	return ""
+}
+
+// SetFlags sets the values of the configuration flags by key, they take precedence over the environment and the configuration file.
+func SetFlags(values map[string]string) {
+	flagValues = values
+}
+
+// SetPath sets the path of the configuration file, it takes precedence over KITE_CONFIG.
+func SetPath(path string) {
+	configPath = path
+}
+
+// Origin returns where the value of the configuration key comes from, e.g. 'env KITE_TIMEZONE'.
+func (c *Config) Origin(key string) string {
+	if origin, ok := c.origins[key]; ok {
+		return origin
+	}
+
+	return OriginDefault
+}
+
+// override returns the value of the field flag or environment variable, and its origin.
+func (f Field) override() (string, string, bool) {
+	if value, ok := flagValues[f.Key]; ok && f.Flag != "" {
+		return value, OriginFlag + " --" + f.Flag, true
+	}
+
+	if value := os.Getenv(f.Env); value != "" && f.Env != "" {
+		return value, OriginEnv + " " + f.Env, true
+	}
+
+	return "", "", false
+}
+
+// Overridden checks whether the field is set by a flag or an environment variable.
+func (f Field) Overridden() bool {
+	_, _, ok := f.override()
+	return ok
+}
+
+// setFileOrigins sets the origin of the keys present in the configuration file.
+func (c *Config) setFileOrigins(data []byte) {
+	var file map[string]json.RawMessage
+
+	if json.Unmarshal(data, &file) != nil {
+		return
+	}
+
+	for _, field := range ConfigFields(c) {
+		_, inFile := file[field.Key]
+
+		// The keys of the sections, e.g. org.team_id, are nested
+		if section, key, ok := strings.Cut(field.Key, "."); ok {
+			var values map[string]json.RawMessage
+
+			_ = json.Unmarshal(file[section], &values)
+			_, inFile = values[key]
+		}
+
+		if inFile {
+			c.origins[field.Key] = OriginFile + " " + c.path
+		}
+	}
+}
+
+// applyOverrides sets the values of the flags and environment variables, either of the secrets or of the other fields.
+// The values of the configuration file are kept to save them instead of the overrides.
+func (c *Config) applyOverrides(secretFields bool) error {
+	for _, field := range Fields() {
+		if field.Secret != secretFields {
+			continue
+		}
+
+		value, origin, ok := field.override()
+
+		if !ok {
+			continue
+		}
+
+		if _, ok := c.fileValues[field.Key]; !ok {
+			c.fileValues[field.Key] = field.get(c)
+		}
+
+		err := field.set(c, value)
+
+		if err != nil {
+			return fmt.Errorf("invalid value of %s: %v", strings.TrimPrefix(origin, OriginFlag+" "), err)
+		}
+
+		c.overrides[field.Key] = field.get(c)
+		c.origins[field.Key] = origin
+	}
+
+	return nil
+}
+
+// fileConfig returns a copy of the configuration with the overridden values reverted to the ones of the configuration file,
+// unless they were changed since the configuration was loaded.
+func (c *Config) fileConfig() *Config {
+	stored := *c
+
+	if c.Org != nil {
+		org := *c.Org
+		stored.Org = &org
+	}
+
+	if c.SOP != nil {
+		sop := *c.SOP
+		stored.SOP = &sop
+	}
+
+	for _, field := range Fields() {
+		override, ok := c.overrides[field.Key]
+
+		if ok && field.get(c) == override {
+			_ = field.set(&stored, c.fileValues[field.Key])
+		}
+	}
+
+	return &stored
+}
+
+// orgField returns the field of an organization ID.
+func orgField(key string, id func(o *Org) *string) Field {
+	return Field{
+		Key: "org." + key,
+		Env: "KITE_ORG_" + strings.ToUpper(key),
+		get: func(c *Config) string {
+			if c.Org == nil {
+				return ""
+			}
+
+			return *id(c.Org)
+		},
+		set: func(c *Config, v string) error {
+			if c.Org == nil {
+				if v == "" {
+					return nil
+				}
+
+				c.Org = &Org{}
+			}
+
+			if v != "" {
+				err := validateID(v)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			*id(c.Org) = v
+
+			return nil
+		},
+		def: func() string {
+			org := DefaultOrg()
+			return *id(&org)
+		},
+	}
+}
+
+// columnPresetField returns the field of a column preset, the columns are validated when used.
+func columnPresetField(name string) Field {
+	return Field{
+		Key: columnPresetsKey + "." + name,
+		get: func(c *Config) string { return c.ColumnPresets[name] },
+		set: func(c *Config, v string) error {
+			if v == "" {
+				delete(c.ColumnPresets, name)
+				return nil
+			}
+
+			if c.ColumnPresets == nil {
+				c.ColumnPresets = map[string]string{}
+			}
+
+			c.ColumnPresets[name] = v
+
+			return nil
+		},
+	}
+}
+
+// keybindingField returns the field of the key of a TUI action.
+func keybindingField(action string) Field {
+	return Field{
+		Key: keybindingsKey + "." + action,
+		get: func(c *Config) string { return c.Keybindings[action] },
+		set: func(c *Config, v string) error {
+			err := validateKeybinding(action, v)
+
+			if err != nil {
+				return err
+			}
+
+			if v == "" {
+				delete(c.Keybindings, action)
+				return nil
+			}
+
+			if c.Keybindings == nil {
+				c.Keybindings = map[string]string{}
+			}
+
+			c.Keybindings[action] = v
+
+			return nil
+		},
+		def: func() string { return DefaultKeybindings()[action] },
+	}
+}
+
+// setTeams sets the teams of the given comma-separated IDs, they are named after their ID.
+func setTeams(c *Config, value string) error {
+	var teams []Team
+
+	for _, id := range splitList(value) {
+		err := validateID(id)
+
+		if err != nil {
+			return err
+		}
+
+		teams = append(teams, Team{ID: id, Name: id})
+	}
+
+	c.Teams = teams
+
+	return nil
+}
+
+func setTimezone(c *Config, value string) error {
+	err := validateTimezone(value)
+
+	if err != nil {
+		return err
+	}
+
+	c.Timezone = value
+
+	return nil
+}
+
+func setRefreshInterval(c *Config, value string) error {
+	err := validateRefreshInterval(value)
+
+	if err != nil {
+		return err
+	}
+
+	c.RefreshInterval = value
+
+	return nil
+}
+
+func setSecretBackend(c *Config, value string) error {
+	if value != "" && value != secrets.PlaintextBackend {
+		_, err := secrets.NewBackend(value, secrets.Options{})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	c.SecretBackend = value
+
+	return nil
+}
+
+func setRelativeTimes(c *Config, value string) error {
+	if value == "" {
+		c.RelativeTimes = false
+		return nil
+	}
+
+	relativeTimes, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return fmt.Errorf("'%s' is not a boolean", value)
+	}
+
+	c.RelativeTimes = relativeTimes
+
+	return nil
+}
+
+func setSOPMaxAge(c *Config, value string) error {
+	err := validateMaxAge(value)
+
+	if err != nil {
+		return err
+	}
+
+	return setSOP(c, func(s *SOP) { s.MaxAge = value })
+}
+
+func setOffline(c *Config, value string) error {
+	offline := false
+
+	if value != "" {
+		var err error
+
+		offline, err = strconv.ParseBool(value)
+
+		if err != nil {
+			return fmt.Errorf("'%s' is not a boolean", value)
+		}
+	}
+
+	return setSOP(c, func(s *SOP) { s.Offline = offline })
+}
+
+// sopValue returns the value of a key of the sop section.
+func sopValue(c *Config, get func(s *SOP) string) string {
+	if c.SOP == nil {
+		return ""
+	}
+
+	return get(c.SOP)
+}
+
+// setSOP sets a key of the sop section, the section is removed from the configuration when empty.
+func setSOP(c *Config, set func(s *SOP)) error {
+	if c.SOP == nil {
+		c.SOP = &SOP{}
+	}
+
+	set(c.SOP)
+
+	if *c.SOP == (SOP{}) {
+		c.SOP = nil
+	}
+
+	return nil
+}
+
+func setHistoryDays(c *Config, value string) error {
+	if value == "" {
+		c.HistoryDays = 0
+		return nil
+	}
+
+	days, err := strconv.Atoi(value)
+
+	if err != nil || days < 1 {
+		return fmt.Errorf("'%s' is not a positive number of days", value)
+	}
+
+	c.HistoryDays = days
+
+	return nil
+}
+
+// validateID validates a PagerDuty ID.
+func validateID(id string) error {
+	if match, _ := regexp.MatchString(constants.PagerDutyIDRegex, id); !match {
+		return fmt.Errorf("'%s' is not a PagerDuty ID", id)
+	}
+
+	return nil
+}
+
+func intValue(value int) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(value)
+}
+
+// splitList splits a comma-separated list.
+func splitList(value string) []string {
+	var values []string
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
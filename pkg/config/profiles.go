@@ -0,0 +1,302 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// DefaultProfile is the profile of the top-level keys of the configuration file.
+const DefaultProfile = "default"
+
+// profilesFile is the content of the configuration file, the top-level keys are the ones of the default profile.
+// The other profiles are kept as-is, only the profile in use is parsed.
+type profilesFile struct {
+	*Config
+
+	// Version is the version of the configuration file format
+	Version int `json:"version,omitempty"`
+
+	// Profile is the active profile, the default profile when unset
+	Profile  string                     `json:"profile,omitempty"`
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty"`
+}
+
+// Profile returns the name of the configuration profile in use.
+func (c *Config) Profile() string {
+	if c == nil || c.profile == "" {
+		return DefaultProfile
+	}
+
+	return c.profile
+}
+
+// Profiles returns the names of the profiles of the configuration file and the name of the profile in use.
+// The default profile is listed unless it is empty.
+func Profiles() ([]string, string, error) {
+	path, err := Find()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	file, err := readProfilesFile(path)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	var names []string
+
+	if data, _ := json.Marshal(file.Config); string(data) != "{}" {
+		names = append(names, DefaultProfile)
+	}
+
+	var profiles []string
+
+	for name := range file.Profiles {
+		profiles = append(profiles, name)
+	}
+
+	sort.Strings(profiles)
+
+	return append(names, profiles...), file.active(), nil
+}
+
+// UseProfile sets the profile used by default, i.e. when neither the --profile flag nor KITE_PROFILE are set.
+func UseProfile(name string) error {
+	path, err := Find()
+
+	if err != nil {
+		return err
+	}
+
+	file, err := readProfilesFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	if _, ok := file.Profiles[name]; !ok && name != DefaultProfile {
+		return fmt.Errorf("profile '%s' not found in the configuration file", name)
+	}
+
+	file.Profile = name
+
+	if name == DefaultProfile {
+		file.Profile = ""
+	}
+
+	return file.write(path)
+}
+
+// DeleteProfile deletes the profile from the configuration file, with the secrets it stored in a secret backend.
+// When the deleted profile is the active one, the default profile becomes active.
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+
+	path, err := Find()
+
+	if err != nil {
+		return err
+	}
+
+	file, err := readProfilesFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	data, ok := file.Profiles[name]
+
+	if !ok {
+		return fmt.Errorf("profile '%s' not found in the configuration file", name)
+	}
+
+	profile := &Config{}
+
+	err = json.Unmarshal(data, profile)
+
+	if err != nil {
+		return fmt.Errorf("error parsing profile '%s': %v", name, err)
+	}
+
+	delete(file.Profiles, name)
+
+	if file.Profile == name {
+		file.Profile = ""
+	}
+
+	err = file.write(path)
+
+	if err != nil {
+		return err
+	}
+
+	// The references are not kept by the configuration holding the secrets file, so that they are deleted
+	secretsConfig := &Config{SecretsFile: profile.SecretsFile}
+
+	return secretsConfig.DeleteSecrets([]string{profile.ApiKeyRef, profile.AccessTokenRef})
+}
+
+// readProfilesFile parses the configuration file and migrates it to the current version, it is empty if the file doesn't exist.
+func readProfilesFile(path string) (*profilesFile, error) {
+	data, err := os.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return &profilesFile{Config: &Config{}, Version: ConfigVersion}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config file")
+	}
+
+	file, _, err := parseProfilesFile(data)
+
+	return file, err
+}
+
+// parseProfilesFile parses the content of the configuration file and migrates it to the current version.
+// It returns whether the file was migrated.
+func parseProfilesFile(data []byte) (*profilesFile, bool, error) {
+	if len(data) == 0 {
+		return nil, false, fmt.Errorf("configuration file is empty")
+	}
+
+	file := &profilesFile{Config: &Config{}}
+
+	err := json.Unmarshal(data, file)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing config file")
+	}
+
+	migrated, err := file.migrate()
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return file, migrated, nil
+}
+
+// active returns the name of the profile in use, set by the --profile flag, KITE_PROFILE or the configuration file.
+func (f *profilesFile) active() string {
+	if field, err := LookupField("profile"); err == nil {
+		if name, _, ok := field.override(); ok && name != "" {
+			return name
+		}
+	}
+
+	if f.Profile != "" {
+		return f.Profile
+	}
+
+	return DefaultProfile
+}
+
+// data returns the content of the given profile.
+func (f *profilesFile) data(name string, raw []byte) ([]byte, error) {
+	if name == DefaultProfile {
+		return raw, nil
+	}
+
+	data, ok := f.Profiles[name]
+
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' not found in the configuration file, run 'kite login --profile %s'", name, name)
+	}
+
+	return data, nil
+}
+
+// set replaces the given profile with the configuration.
+func (f *profilesFile) set(name string, c *Config) error {
+	if name == DefaultProfile {
+		f.Config = c
+		return nil
+	}
+
+	data, err := json.Marshal(c)
+
+	if err != nil {
+		return fmt.Errorf("cannot marshal configuration file: %v", err)
+	}
+
+	if f.Profiles == nil {
+		f.Profiles = map[string]json.RawMessage{}
+	}
+
+	f.Profiles[name] = data
+
+	return nil
+}
+
+// write saves the profiles to the configuration file.
+func (f *profilesFile) write(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("cannot marshal configuration file: %v", err)
+	}
+
+	err = os.WriteFile(path, data, 0600)
+
+	if err != nil {
+		return fmt.Errorf("cannot save configuration file '%s': %v", path, err)
+	}
+
+	return nil
+}
+
+// Validate parses the content of a configuration file and validates the values of its profiles, which it returns by name.
+func Validate(data []byte) (map[string]*Config, error) {
+	file, _, err := parseProfilesFile(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := map[string]*Config{DefaultProfile: file.Config}
+
+	for name, data := range file.Profiles {
+		profile := &Config{}
+
+		err = json.Unmarshal(data, profile)
+
+		if err != nil {
+			return nil, fmt.Errorf("error parsing profile '%s': %v", name, err)
+		}
+
+		profiles[name] = profile
+	}
+
+	for name, profile := range profiles {
+		if profile.ApiKey != "" {
+			_, err = ValidateKey(profile.ApiKey)
+
+			if err != nil {
+				return nil, fmt.Errorf("profile '%s': %v", name, err)
+			}
+		}
+
+		for _, field := range ConfigFields(profile) {
+			err = field.Set(&Config{}, field.get(profile))
+
+			if err != nil {
+				return nil, fmt.Errorf("profile '%s': %v", name, err)
+			}
+		}
+
+		err = profile.ValidateKeybindings()
+
+		if err != nil {
+			return nil, fmt.Errorf("profile '%s': %v", name, err)
+		}
+	}
+
+	return profiles, nil
+}
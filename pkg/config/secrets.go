@@ -0,0 +1,223 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secrets"
+)
+
+// MigrateSecrets moves the API key and the GitHub token to the given secret backend, they are stored on save.
+// The command backend references the commands printing the secrets, the plaintext backend stores them in the config file.
+func (c *Config) MigrateSecrets(backend string, apiKeyCommand string, accessTokenCommand string) error {
+	switch backend {
+	case secrets.PlaintextBackend:
+		c.SecretBackend = ""
+		c.ApiKeyRef = ""
+		c.AccessTokenRef = ""
+
+	case secrets.CommandBackend:
+		if apiKeyCommand == "" {
+			return fmt.Errorf("please provide the command printing the API key")
+		}
+
+		c.SecretBackend = backend
+		c.ApiKeyRef = secrets.Ref(backend, apiKeyCommand)
+		c.AccessTokenRef = ""
+
+		if accessTokenCommand != "" {
+			c.AccessTokenRef = secrets.Ref(backend, accessTokenCommand)
+		}
+
+		return c.resolveSecrets()
+
+	case secrets.KeyringBackend, secrets.FileBackend:
+		c.SecretBackend = backend
+
+	default:
+		_, err := secrets.NewBackend(backend, secrets.Options{})
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSecrets deletes the secrets of the given references from their backend, unless they are still referenced.
+func (c *Config) DeleteSecrets(refs []string) error {
+	opts := c.secretOptions()
+
+	for _, ref := range refs {
+		if ref == "" || ref == c.ApiKeyRef || ref == c.AccessTokenRef {
+			continue
+		}
+
+		backendName, name, err := secrets.ParseRef(ref)
+
+		if err != nil {
+			return err
+		}
+
+		backend, err := secrets.NewBackend(backendName, opts)
+
+		if err != nil {
+			return err
+		}
+
+		err = backend.Delete(name)
+
+		if err != nil {
+			return fmt.Errorf("cannot delete secret '%s': %v", ref, err)
+		}
+	}
+
+	return nil
+}
+
+// resolveSecrets reads the API key and the GitHub token from their secret backend.
+func (c *Config) resolveSecrets() error {
+	opts := c.secretOptions()
+	c.resolved = map[string]string{}
+
+	if c.origins == nil {
+		c.origins = map[string]string{}
+	}
+
+	for _, secret := range []struct {
+		key   string
+		ref   string
+		value *string
+	}{
+		{"api_key", c.ApiKeyRef, &c.ApiKey},
+		{"gh_token", c.AccessTokenRef, &c.AccessToken},
+	} {
+		// The secrets overridden by a flag or the environment are not read
+		if field, _ := LookupField(secret.key); secret.ref == "" || field.Overridden() {
+			continue
+		}
+
+		value, err := secrets.Resolve(secret.ref, opts)
+
+		if err != nil {
+			return err
+		}
+
+		*secret.value = value
+		c.resolved[secret.ref] = value
+		c.origins[secret.key] = OriginSecret + " " + secret.ref
+	}
+
+	return nil
+}
+
+// storeSecrets stores the API key and the GitHub token in the secret backend, they are then only referenced in the configuration.
+func (c *Config) storeSecrets() error {
+	var err error
+
+	c.ApiKeyRef, err = c.storeSecret(c.secretName(constants.APIKeySecret), c.ApiKey, c.ApiKeyRef)
+
+	if err != nil {
+		return err
+	}
+
+	c.AccessTokenRef, err = c.storeSecret(c.secretName(constants.AccessTokenSecret), c.AccessToken, c.AccessTokenRef)
+
+	if err != nil {
+		return err
+	}
+
+	if c.ApiKeyRef != "" {
+		c.ApiKey = ""
+	}
+
+	if c.AccessTokenRef != "" {
+		c.AccessToken = ""
+	}
+
+	return nil
+}
+
+// secretName returns the name of the secret in the backend, the secrets of the profiles are suffixed with the profile name.
+func (c *Config) secretName(name string) string {
+	if c.Profile() == DefaultProfile {
+		return name
+	}
+
+	return name + "." + c.Profile()
+}
+
+// storeSecret stores the secret in the secret backend and returns its reference, the reference is empty if the secret is stored in plaintext.
+// The backend is the configured one, or the one the secret is already stored in.
+func (c *Config) storeSecret(name string, secret string, ref string) (string, error) {
+	if secret == "" {
+		// The secret was not read from its backend, e.g. it is overridden by the environment
+		if _, resolved := c.resolved[ref]; ref != "" && !resolved {
+			return ref, nil
+		}
+
+		return "", nil
+	}
+
+	backend := c.SecretBackend
+
+	if backend == "" && ref != "" {
+		backend, _, _ = secrets.ParseRef(ref)
+	}
+
+	// The secret is unchanged
+	if ref != "" && c.resolved[ref] == secret {
+		if refBackend, _, _ := secrets.ParseRef(ref); refBackend == backend {
+			return ref, nil
+		}
+	}
+
+	switch backend {
+	case "", secrets.PlaintextBackend:
+		return "", nil
+
+	case secrets.CommandBackend:
+		return "", fmt.Errorf("the %s secret is read from a command, please update it with your secret manager", name)
+	}
+
+	ref, err := secrets.Store(backend, name, secret, c.secretOptions())
+
+	if err != nil {
+		return "", err
+	}
+
+	if c.resolved == nil {
+		c.resolved = map[string]string{}
+	}
+
+	c.resolved[ref] = secret
+
+	return ref, nil
+}
+
+// secretOptions returns the options of the secret backends.
+// The secrets file defaults to the configuration directory, its passphrase is asked once.
+func (c *Config) secretOptions() secrets.Options {
+	path := c.SecretsFile
+
+	if path == "" {
+		configPath, _ := Find()
+		path = filepath.Join(filepath.Dir(configPath), constants.SecretsFilename)
+	}
+
+	return secrets.Options{
+		File: path,
+		Passphrase: func() (string, error) {
+			if c.passphrase == "" {
+				passphrase, err := secrets.PromptPassphrase()
+
+				if err != nil {
+					return "", err
+				}
+
+				c.passphrase = passphrase
+			}
+
+			return c.passphrase, nil
+		},
+	}
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// Actions of the TUI which can be bound to another key
+const (
+	ActionRefresh             = "refresh"
+	ActionSwitchView          = "switch_view"
+	ActionToggleSuppressed    = "toggle_suppressed"
+	ActionToggleRelativeTimes = "toggle_relative_times"
+	ActionIncidentAlerts      = "incident_alerts"
+	ActionRequestResponders   = "request_responders"
+	ActionClusterLogin        = "cluster_login"
+	ActionServiceLogs         = "service_logs"
+	ActionEscalationChain     = "escalation_chain"
+	ActionClusterHistory      = "cluster_history"
+	ActionSOP                 = "sop"
+	ActionSOPSearch           = "sop_search"
+	ActionPreviousDay         = "previous_day"
+	ActionNextDay             = "next_day"
+	ActionGoToDate            = "go_to_date"
+	ActionCreateOverride      = "create_override"
+	ActionListOverrides       = "list_overrides"
+	ActionDeleteOverride      = "delete_override"
+	ActionNextOncall          = "next_oncall"
+	ActionAllTeamsOncall      = "all_teams_oncall"
+)
+
+// Actions of each TUI page, the keys of the actions of a page must be different
+var (
+	AlertsPageActions = []string{
+		ActionRefresh,
+		ActionSwitchView,
+		ActionToggleSuppressed,
+		ActionToggleRelativeTimes,
+		ActionSOPSearch,
+	}
+
+	IncidentsPageActions = []string{
+		ActionIncidentAlerts,
+		ActionRequestResponders,
+	}
+
+	AlertDetailsPageActions = []string{
+		ActionClusterLogin,
+		ActionServiceLogs,
+		ActionRequestResponders,
+		ActionEscalationChain,
+		ActionClusterHistory,
+		ActionSOP,
+		ActionSOPSearch,
+	}
+
+	OncallPageActions = []string{
+		ActionPreviousDay,
+		ActionNextDay,
+		ActionGoToDate,
+		ActionCreateOverride,
+		ActionListOverrides,
+		ActionToggleRelativeTimes,
+		ActionNextOncall,
+		ActionAllTeamsOncall,
+	}
+
+	OverridesPageActions = []string{
+		ActionDeleteOverride,
+	}
+)
+
+// DefaultKeybindings returns the default keys of the TUI actions.
+func DefaultKeybindings() map[string]string {
+	return map[string]string{
+		ActionRefresh:             "r",
+		ActionSwitchView:          "v",
+		ActionToggleSuppressed:    "h",
+		ActionToggleRelativeTimes: "z",
+		ActionIncidentAlerts:      "v",
+		ActionRequestResponders:   "p",
+		ActionClusterLogin:        "y",
+		ActionServiceLogs:         "l",
+		ActionEscalationChain:     "e",
+		ActionClusterHistory:      "c",
+		ActionSOP:                 "s",
+		ActionSOPSearch:           "/",
+		ActionPreviousDay:         "[",
+		ActionNextDay:             "]",
+		ActionGoToDate:            "g",
+		ActionCreateOverride:      "o",
+		ActionListOverrides:       "l",
+		ActionDeleteOverride:      "d",
+		ActionNextOncall:          "n",
+		ActionAllTeamsOncall:      "a",
+	}
+}
+
+// Actions returns the names of the TUI actions which can be bound to another key.
+func Actions() []string {
+	var actions []string
+
+	for action := range DefaultKeybindings() {
+		actions = append(actions, action)
+	}
+
+	sort.Strings(actions)
+
+	return actions
+}
+
+// Keybinding returns the key of the TUI action, the configured one or its default.
+func (c *Config) Keybinding(action string) string {
+	if c != nil {
+		if key, ok := c.Keybindings[action]; ok && key != "" {
+			return strings.ToLower(key)
+		}
+	}
+
+	return DefaultKeybindings()[action]
+}
+
+// Refresh returns the interval of the alerts automatic refresh, zero when the alerts are refreshed on demand only.
+func (c *Config) Refresh() time.Duration {
+	if c == nil || c.RefreshInterval == "" {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(c.RefreshInterval)
+
+	if err != nil {
+		return 0
+	}
+
+	return interval
+}
+
+// Columns returns the columns of the given preset, or the given columns if they don't name a preset.
+func (c *Config) Columns(columns string) string {
+	if c != nil {
+		if preset, ok := c.ColumnPresets[columns]; ok {
+			return preset
+		}
+	}
+
+	return columns
+}
+
+// ValidateKeybindings checks the actions of each TUI page have different keys, an action would otherwise hide another one.
+func (c *Config) ValidateKeybindings() error {
+	for _, actions := range [][]string{AlertsPageActions, IncidentsPageActions, AlertDetailsPageActions, OncallPageActions, OverridesPageActions} {
+		bound := map[string]string{}
+
+		for _, action := range actions {
+			key := c.Keybinding(action)
+
+			if other, ok := bound[key]; ok {
+				return fmt.Errorf("the '%s' key of the %s action is already the key of the %s action", key, action, other)
+			}
+
+			bound[key] = action
+		}
+	}
+
+	return nil
+}
+
+// validateKeybinding validates the key of a TUI action, it is a single printable character.
+func validateKeybinding(action string, key string) error {
+	if _, ok := DefaultKeybindings()[action]; !ok {
+		return fmt.Errorf("unknown action '%s', valid actions are: %s", action, strings.Join(Actions(), ", "))
+	}
+
+	r, size := utf8.DecodeRuneInString(key)
+
+	if key != "" && (size != len(key) || !unicode.IsPrint(r) || unicode.IsSpace(r)) {
+		return fmt.Errorf("'%s' is not a single character", key)
+	}
+
+	return nil
+}
+
+// validateRefreshInterval validates the interval of the alerts automatic refresh, e.g. 30s or 5m.
+func validateRefreshInterval(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(value)
+
+	if err != nil {
+		return fmt.Errorf("'%s' is not a duration, e.g. 30s or 5m", value)
+	}
+
+	if interval < constants.MinRefreshInterval*time.Second {
+		return fmt.Errorf("the refresh interval must be at least %ds", constants.MinRefreshInterval)
+	}
+
+	return nil
+}
+
+// validateTimezone validates the display timezone, local, UTC or a named timezone.
+func validateTimezone(value string) error {
+	switch strings.ToLower(value) {
+	case "", "utc", "local":
+		return nil
+	}
+
+	_, err := time.LoadLocation(value)
+
+	if err != nil {
+		return fmt.Errorf("invalid timezone '%s', use local, UTC or a named timezone e.g. Europe/Prague", value)
+	}
+
+	return nil
+}
+
+// validateMaxAge validates the age after which the cached SOPs are validated again, e.g. 30m or 24h.
+func validateMaxAge(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	maxAge, err := time.ParseDuration(value)
+
+	if err != nil || maxAge < 0 {
+		return fmt.Errorf("'%s' is not a duration, e.g. 30m or 24h", value)
+	}
+
+	return nil
+}
@@ -19,25 +19,46 @@ package constants
 const (
 	ConfigFilepath = "kite/config.json"
 
+	// Encrypted secrets file, in the configuration directory, and the names of the secrets
+	SecretsFilename   = "secrets.enc"
+	APIKeySecret      = "pagerduty_api_key"
+	AccessTokenSecret = "github_token"
+
 	APIKeyURL       = "https://support.pagerduty.com/docs/generating-api-keys#generating-a-personal-rest-api-key"
 	AccessTokenURL  = "https://docs.github.com/en/authentication/keeping-your-account-and-data-secure/creating-a-personal-access-token"
 	OcmContainerURL = "https://github.com/openshift/ocm-container"
 	OcmContainer    = "ocm-container"
+	Ocm             = "ocm"
+	OcmURL          = "https://github.com/openshift-online/ocm-cli"
 	Shell           = "SHELL"
 
+	// GitHub repository of the SOPs
+	SOPRepoOwner = "openshift"
+	SOPRepo      = "ops-sop"
+	SOPRepoRef   = "master"
+
+	// Hours after which the SOP search index downloaded from GitHub is downloaded again
+	SOPIndexMaxAge = 24
+
+	// Default minutes after which a cached SOP is validated again with GitHub
+	SOPMaxAge = 60
+
 	// Regex
-	APIKeyRegex     = "^[a-z|A-Z0-9+_-]{20}$"
-	IncidentIdRegex = "^[A-Z0-9]{7,14}$"
-	TeamIdRegex     = "^[A-Z0-9]{7}$"
+	APIKeyRegex      = "^[a-z|A-Z0-9+_-]{20}$"
+	IncidentIdRegex  = "^[A-Z0-9]{7,14}$"
+	TeamIdRegex      = "^[A-Z0-9]{7}$"
+	PagerDutyIDRegex = "^[A-Z0-9]{7,14}$"
 
 	// Sample API key for testing
 	SampleKey = "y_NbAkKc66ryYTWUXYEu"
 
-	// Set limit to number of incidents fetched from pagerduty
-	IncidentsLimit          = 10
-	TrigerredIncidentsLimit = 25
+	// Number of days of incidents searched for the cluster history
+	HistoryDays = 14
+
+	// Minimum interval of the alerts automatic refresh, in seconds
+	MinRefreshInterval = 10
 
-	// PagerDuty IDs
+	// PagerDuty IDs, defaults of the org section of the configuration file
 	TeamID     = "PASPK4G"
 	SilentTest = "P8QS6CC"
 	NobodySREP = "P53J4TK"
@@ -50,10 +71,12 @@ const (
 	// PagerDuty Incident Statuses
 	StatusTriggered    = "triggered"
 	StatusAcknowledged = "acknowledged"
+	StatusResolved     = "resolved"
 	StatusHigh         = "high"
 	StatusLow          = "low"
 
 	//ScheduleIDS for fetching oncalls as per pagerduty documentation (https://<host>.pagerduty.com/escalation_policies)
+	//Defaults of the org section of the configuration file
 	PrimaryScheduleID   = "P995J2A"
 	SecondaryScheduleID = "P4TU2IT"
 	OncallIDWeekend     = "P7CC7UN"
@@ -0,0 +1,274 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ocm"
+)
+
+// Statuses of the checks
+const (
+	Pass = "pass"
+	Warn = "warn"
+	Fail = "fail"
+)
+
+// Rate limit headers of the PagerDuty REST API
+const (
+	RateLimitHeader          = "ratelimit-limit"
+	RateLimitRemainingHeader = "ratelimit-remaining"
+)
+
+// rateLimitWarning is the share of the rate limit below which the remaining requests are reported
+const rateLimitWarning = 0.2
+
+// Check is the result of a check of the kite setup.
+type Check struct {
+	Name    string
+	Status  string
+	Message string
+}
+
+// Failed checks whether one of the checks failed.
+func Failed(checks []Check) bool {
+	for _, check := range checks {
+		if check.Status == Fail {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run checks the configuration file, the credentials and the tools kite depends on.
+// The configuration is read without validating it, the checks depending on a configuration which can't be read are skipped.
+func Run() []Check {
+	var checks []Check
+
+	path, err := config.Find()
+
+	if err != nil {
+		return []Check{{"config file", Fail, err.Error()}}
+	}
+
+	checks = append(checks, ConfigFile(path))
+
+	cfg, err := config.Read()
+
+	if err != nil && !os.IsNotExist(err) {
+		checks = append(checks, Check{"config", Fail, err.Error()})
+	}
+
+	if err == nil {
+		checks = append(checks, APIKey(cfg)...)
+		checks = append(checks, GitHubToken(cfg))
+
+		if cfg.SOPPath() != "" {
+			checks = append(checks, SOPClone(cfg.SOPPath()))
+		}
+	}
+
+	checks = append(checks,
+		Executable(constants.OcmContainer, constants.OcmContainerURL),
+		Executable(constants.Ocm, constants.OcmURL),
+		OCMLogin(),
+		Terminal(cfg),
+	)
+
+	return checks
+}
+
+// ConfigFile checks the configuration file exists and is only readable by the user, it may store the secrets.
+func ConfigFile(path string) Check {
+	name := "config file"
+
+	info, err := os.Stat(path)
+
+	if os.IsNotExist(err) {
+		if field, _ := config.LookupField("api_key"); field.Overridden() {
+			return Check{name, Warn, fmt.Sprintf("%s not found, the configuration is read from the flags and the environment", path)}
+		}
+
+		return Check{name, Fail, fmt.Sprintf("%s not found, run the 'kite login' command", path)}
+	}
+
+	if err != nil {
+		return Check{name, Fail, err.Error()}
+	}
+
+	if mode := info.Mode().Perm(); mode&0077 != 0 {
+		return Check{name, Warn, fmt.Sprintf("%s permissions are %04o, run 'chmod 600 %s' as it may store secrets", path, mode, path)}
+	}
+
+	return Check{name, Pass, path}
+}
+
+// APIKey checks the format of the PagerDuty API key, then its validity and the remaining requests of the rate limit.
+func APIKey(cfg *config.Config) []Check {
+	_, err := config.ValidateKey(cfg.ApiKey)
+
+	if err != nil {
+		return []Check{{"pagerduty api key", Fail, "the API key format is invalid, run the 'kite login' command"}}
+	}
+
+	recorder := &headerRecorder{client: http.DefaultClient}
+	client := pdApi.NewClient(cfg.ApiKey)
+	client.HTTPClient = recorder
+
+	user, err := client.GetCurrentUser(pdApi.GetCurrentUserOptions{})
+
+	var apiError pdApi.APIError
+
+	if errors.As(err, &apiError) {
+		return []Check{{"pagerduty api key", Fail, fmt.Sprintf("the API key is rejected: %v", apiError.StatusCode)}}
+	}
+
+	if err != nil {
+		return []Check{{"pagerduty api key", Fail, fmt.Sprintf("cannot reach the PagerDuty API: %v", err)}}
+	}
+
+	return []Check{
+		{"pagerduty api key", Pass, fmt.Sprintf("logged in as %s", user.Name)},
+		RateLimit(recorder.header),
+	}
+}
+
+// RateLimit checks the remaining requests of the PagerDuty rate limit from the headers of a response.
+func RateLimit(header http.Header) Check {
+	name := "pagerduty rate limit"
+
+	limit, err := strconv.Atoi(header.Get(RateLimitHeader))
+
+	if err != nil || limit <= 0 {
+		return Check{name, Warn, "the rate limit is not reported by the API"}
+	}
+
+	remaining, err := strconv.Atoi(header.Get(RateLimitRemainingHeader))
+
+	if err != nil {
+		return Check{name, Warn, "the remaining requests are not reported by the API"}
+	}
+
+	message := fmt.Sprintf("%d/%d requests remaining", remaining, limit)
+
+	if remaining == 0 {
+		return Check{name, Fail, message}
+	}
+
+	if float64(remaining) < float64(limit)*rateLimitWarning {
+		return Check{name, Warn, message}
+	}
+
+	return Check{name, Pass, message}
+}
+
+// GitHubToken checks the GitHub token can read the SOP repository, and warns about the scopes which are not read-only.
+func GitHubToken(cfg *config.Config) Check {
+	name := "github token"
+
+	if cfg.AccessToken == "" {
+		return Check{name, Warn, "no GitHub token, the SOPs can only be opened in a browser, run the 'kite login' command to view them in kite"}
+	}
+
+	scopes, err := config.ValidateGHToken(cfg.AccessToken)
+
+	if err != nil {
+		return Check{name, Fail, fmt.Sprintf("the token can't access %s/%s: %v", constants.SOPRepoOwner, constants.SOPRepo, err)}
+	}
+
+	if len(scopes) == 0 {
+		return Check{name, Pass, fmt.Sprintf("access to %s/%s", constants.SOPRepoOwner, constants.SOPRepo)}
+	}
+
+	var broad []string
+
+	for _, scope := range scopes {
+		if strings.HasPrefix(scope, "admin:") || strings.HasPrefix(scope, "write:") || strings.HasPrefix(scope, "delete") {
+			broad = append(broad, scope)
+		}
+	}
+
+	if len(broad) > 0 {
+		return Check{name, Warn, fmt.Sprintf("the token has more scopes than needed: %s", strings.Join(broad, ", "))}
+	}
+
+	return Check{name, Pass, fmt.Sprintf("access to %s/%s, scopes: %s", constants.SOPRepoOwner, constants.SOPRepo, strings.Join(scopes, ", "))}
+}
+
+// SOPClone checks the local clone of the SOP repository is a git clone.
+func SOPClone(path string) Check {
+	name := "sop clone"
+
+	info, err := os.Stat(filepath.Join(path, ".git"))
+
+	if err != nil || !info.IsDir() {
+		return Check{name, Fail, fmt.Sprintf("%s is not a git clone of %s/%s", path, constants.SOPRepoOwner, constants.SOPRepo)}
+	}
+
+	return Check{name, Pass, path}
+}
+
+// Executable checks the executable is found in the PATH.
+func Executable(name string, url string) Check {
+	path, err := exec.LookPath(name)
+
+	if err != nil {
+		return Check{name, Warn, fmt.Sprintf("%s is not found, please install it via: %s", name, url)}
+	}
+
+	return Check{name, Pass, path}
+}
+
+// OCMLogin checks the user is logged in to OCM, the service logs need it.
+func OCMLogin() Check {
+	url, err := ocm.LoginURL()
+
+	if err != nil {
+		return Check{"ocm login", Warn, err.Error()}
+	}
+
+	return Check{"ocm login", Pass, fmt.Sprintf("logged in to %s", url)}
+}
+
+// Terminal checks the terminal emulator opening the cluster logins is installed.
+func Terminal(cfg *config.Config) Check {
+	name := "terminal emulator"
+
+	if cfg == nil || cfg.Terminal == "" {
+		return Check{name, Warn, "no terminal emulator selected, run the 'kite terminal' command"}
+	}
+
+	path, err := exec.LookPath(cfg.Terminal)
+
+	if err != nil {
+		return Check{name, Fail, fmt.Sprintf("%s is not found, run the 'kite terminal' command", cfg.Terminal)}
+	}
+
+	return Check{name, Pass, path}
+}
+
+// headerRecorder keeps the headers of the last response.
+type headerRecorder struct {
+	client *http.Client
+	header http.Header
+}
+
+func (r *headerRecorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.client.Do(req)
+
+	if resp != nil {
+		r.header = resp.Header
+	}
+
+	return resp, err
+}
@@ -0,0 +1,29 @@
+package ocm
+
+import (
+	"fmt"
+
+	"github.com/openshift-online/ocm-cli/pkg/config"
+)
+
+// LoginURL returns the URL of the OCM API the user is logged in to.
+// It fails if the user is not logged in or the tokens are expired, the tokens are not checked against the API.
+func LoginURL() (string, error) {
+	cfg, err := config.Load()
+
+	if err != nil {
+		return "", err
+	}
+
+	armed, reason, err := cfg.Armed()
+
+	if err != nil {
+		return "", err
+	}
+
+	if !armed {
+		return "", fmt.Errorf("not logged in to OCM, %s, run the 'ocm login' command", reason)
+	}
+
+	return cfg.URL, nil
+}
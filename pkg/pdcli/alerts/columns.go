@@ -0,0 +1,240 @@
+package pdcli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+const (
+	// DefaultAlertColumns are the columns displayed in the alerts table.
+	DefaultAlertColumns = "incident.id,alert.id,alert,cluster.name,cluster.id,status,severity"
+
+	// DefaultIncidentColumns are the columns displayed in the incidents tables.
+	// The incident ID has to be the first column as the incidents tables are keyed by it.
+	DefaultIncidentColumns = "incident.id,incident.title,urgency,incident.status,service,assignee"
+
+	// TeamColumn is the column appended to the default columns when alerts of more than one team are displayed.
+	TeamColumn = "team"
+
+	// BodyColumnPrefix is the prefix of the columns that display a value of the raw alert body.
+	BodyColumnPrefix = "body."
+)
+
+// Column is a table column that can be displayed for an alert or an incident.
+type Column struct {
+	Key    string
+	Header string
+
+	value     func(a Alert) string
+	sortValue func(a Alert) string
+}
+
+// Value returns the column value for the given alert.
+func (c Column) Value(a Alert) string {
+	return c.value(a)
+}
+
+// SortValue returns the value the given alert is sorted by for this column.
+func (c Column) SortValue(a Alert) string {
+	if c.sortValue != nil {
+		return c.sortValue(a)
+	}
+
+	return c.value(a)
+}
+
+// columnRegistry contains all the columns supported by the --columns option.
+var columnRegistry = []Column{
+	{Key: "incident.id", Header: "INCIDENT ID", value: func(a Alert) string { return a.IncidentID }},
+	{Key: "incident.title", Header: "TITLE", value: func(a Alert) string { return a.IncidentTitle }},
+	{Key: "incident.status", Header: "INCIDENT STATUS", value: func(a Alert) string { return a.IncidentStatus }},
+	{
+		Key:       "incident.created",
+		Header:    "CREATED",
+		value:     func(a Alert) string { return utils.DisplayTimestamp(a.CreatedAt) },
+		sortValue: func(a Alert) string { return a.CreatedAt },
+	},
+	{
+		Key:    "incident.age",
+		Header: "AGE",
+		value:  func(a Alert) string { return age(a.CreatedAt) },
+		// The oldest incident has the earliest creation timestamp
+		sortValue: func(a Alert) string { return a.CreatedAt },
+	},
+	{Key: "alert.id", Header: "ALERT ID", value: func(a Alert) string { return a.AlertID }},
+	{Key: "alert", Header: "ALERT", value: func(a Alert) string { return a.Name }},
+	{Key: "cluster.name", Header: "CLUSTER NAME", value: func(a Alert) string { return a.ClusterName }},
+	{Key: "cluster.id", Header: "CLUSTER ID", value: func(a Alert) string { return a.ClusterID }},
+	{Key: "status", Header: "STATUS", value: func(a Alert) string { return a.Status }},
+	{Key: "severity", Header: "SEVERITY", value: func(a Alert) string { return a.Severity }},
+	{Key: "urgency", Header: "URGENCY", value: func(a Alert) string { return a.Urgency }},
+	{Key: "priority", Header: "PRIORITY", value: func(a Alert) string { return a.Priority }},
+	{Key: "assignee", Header: "ASSIGNED TO", value: func(a Alert) string { return a.Assignee }},
+	{Key: "service", Header: "SERVICE", value: func(a Alert) string { return a.Service }},
+	{Key: "team", Header: "TEAM", value: func(a Alert) string { return a.Team }},
+	{Key: "escalation.policy", Header: "ESCALATION POLICY", value: func(a Alert) string { return a.EscalationPolicy }},
+	{Key: "acknowledger", Header: "ACKNOWLEDGED BY", value: func(a Alert) string { return a.Acknowledger }},
+	{
+		Key:       "alert.count",
+		Header:    "ALERTS",
+		value:     func(a Alert) string { return fmt.Sprint(a.AlertCount) },
+		sortValue: func(a Alert) string { return fmt.Sprintf("%010d", a.AlertCount) },
+	},
+	{
+		Key:       "status.changed",
+		Header:    "LAST STATUS CHANGE",
+		value:     func(a Alert) string { return utils.DisplayTimestamp(a.LastStatusChange) },
+		sortValue: func(a Alert) string { return a.LastStatusChange },
+	},
+	{
+		Key:       "cluster.check_in",
+		Header:    "LAST CHECK-IN",
+		value:     func(a Alert) string { return utils.DisplayTimestamp(a.LastCheckIn) },
+		sortValue: func(a Alert) string { return a.LastCheckIn },
+	},
+	{Key: "sop", Header: "SOP", value: sopPresent},
+}
+
+// ColumnKeys returns the keys of all the supported columns.
+func ColumnKeys() []string {
+	var keys []string
+
+	for _, c := range columnRegistry {
+		keys = append(keys, c.Key)
+	}
+
+	return append(keys, BodyColumnPrefix+"<path>")
+}
+
+// LookupColumn returns the column for the given column key.
+func LookupColumn(key string) (Column, error) {
+	key = strings.TrimSpace(key)
+
+	if strings.HasPrefix(key, BodyColumnPrefix) && len(key) > len(BodyColumnPrefix) {
+		path := strings.TrimPrefix(key, BodyColumnPrefix)
+
+		return Column{
+			Key:    key,
+			Header: strings.ToUpper(path),
+			value:  func(a Alert) string { return bodyValue(a.Body, path) },
+		}, nil
+	}
+
+	for _, c := range columnRegistry {
+		if c.Key == key {
+			return c, nil
+		}
+	}
+
+	return Column{}, fmt.Errorf("unknown column '%s', valid columns are: %s", key, strings.Join(ColumnKeys(), ", "))
+}
+
+// ParseColumns parses a comma separated list of column keys into columns.
+func ParseColumns(cols string) ([]Column, error) {
+	var columns []Column
+
+	for _, key := range strings.Split(cols, ",") {
+		if strings.TrimSpace(key) == "" {
+			continue
+		}
+
+		column, err := LookupColumn(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns specified")
+	}
+
+	return columns, nil
+}
+
+// GetTableData parses and returns tabular data for the given alerts, i.e table headers and rows.
+// The columns are displayed in the order they are specified in.
+func GetTableData(alerts []Alert, cols string) ([]string, [][]string, error) {
+	var headers []string
+	var tableData [][]string
+
+	columns, err := ParseColumns(cols)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, c := range columns {
+		headers = append(headers, c.Header)
+	}
+
+	for _, alert := range alerts {
+		var values []string
+
+		for _, c := range columns {
+			values = append(values, c.Value(alert))
+		}
+
+		tableData = append(tableData, values)
+	}
+
+	return headers, tableData, nil
+}
+
+// IncidentRows converts the given incidents into table rows so that they can be displayed using the column registry.
+func IncidentRows(incidents []pdApi.Incident) []Alert {
+	var rows []Alert
+
+	for _, incident := range incidents {
+		row := Alert{}
+		row.ParseIncidentData(incident)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// bodyValue returns the value found at the given dot separated path of the alert body.
+func bodyValue(body map[string]interface{}, path string) string {
+	var value interface{} = body
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+
+		if !ok {
+			return "N/A"
+		}
+
+		if value, ok = m[key]; !ok || value == nil {
+			return "N/A"
+		}
+	}
+
+	return fmt.Sprint(value)
+}
+
+// age returns the time elapsed since the given PagerDuty timestamp.
+func age(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+
+	if err != nil {
+		return ""
+	}
+
+	return utils.FormatDuration(time.Since(t))
+}
+
+// sopPresent returns whether the alert has an SOP link.
+func sopPresent(a Alert) string {
+	if a.Sop == "" || a.Sop == "<nil>" {
+		return "no"
+	}
+
+	return "yes"
+}
@@ -3,7 +3,6 @@ package pdcli
 import (
 	"errors"
 	"fmt"
-	"sort"
 	"strings"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
@@ -29,49 +28,114 @@ type Alert struct {
 	Token       string
 	Tags        string
 	WebURL      string
+
+	// Incident related
+	IncidentTitle    string
+	IncidentStatus   string
+	CreatedAt        string
+	Urgency          string
+	Priority         string
+	Assignee         string
+	Service          string
+	ServiceID        string
+	EscalationPolicy string
+	Acknowledger     string
+	AlertCount       uint
+	LastStatusChange string
+	Team             string
+
+	// Raw alert body, used by the body.<path> columns
+	Body map[string]interface{}
 }
 
 var (
 	TrigerredAlerts []Alert
 )
 
+// incidentsPageSize is the number of incidents fetched per request, the maximum of the PagerDuty API
+const incidentsPageSize = 100
+
 // GetIncidents returns a slice of pagerduty incidents.
+// The incidents hidden by the suppression policy are not included.
 func GetIncidents(c client.PagerDutyClient, opts *pdApi.ListIncidentsOptions) ([]pdApi.Incident, error) {
-	var aerr pdApi.APIError
+	incidents, _, err := GetAllIncidents(c, opts)
+
+	return incidents, err
+}
+
+// GetAllIncidents returns the pagerduty incidents and the incidents hidden by the suppression policy.
+func GetAllIncidents(c client.PagerDutyClient, opts *pdApi.ListIncidentsOptions) ([]pdApi.Incident, []pdApi.Incident, error) {
 	var incidents []pdApi.Incident
+	var hidden []pdApi.Incident
 
 	// Check if incidents are fetched for a Team
 	isTeam := len(opts.TeamIDs) > 0
 
 	// Get incidents via pagerduty API
-	incidentsList, err := c.ListIncidents(*opts)
+	incidentsList, err := ListIncidents(c, *opts)
 
 	if err != nil {
-		if errors.As(err, &aerr) {
-			if aerr.RateLimited() {
-				return nil, fmt.Errorf("API rate limited")
-			}
-			return nil, fmt.Errorf("status code: %d, error: %s", aerr.StatusCode, err)
-		}
+		return nil, nil, err
 	}
 
-	for _, incident := range incidentsList.Incidents {
-		// When incidents are fetched for a team, do not include the incidents assigned to SilentTest
-		if isTeam && (incident.EscalationPolicy.ID == constants.SilentTestEscalationPolicyID ||
-			incident.EscalationPolicy.ID == constants.CADSilentTestEscalationPolicyID ||
-			incident.EscalationPolicy.ID == constants.CADSilentTestStageEscalationPolicyID ||
-			incident.Assignments[0].Assignee.ID == constants.SilentTest ||
-			incident.Assignments[0].Assignee.ID == constants.NobodySREP) {
+	for _, incident := range incidentsList {
+		// When incidents are fetched for a team, do not include the suppressed incidents e.g. assigned to Silent Test
+		if isTeam && IncidentSuppression.Suppressed(incident) {
+			hidden = append(hidden, incident)
 			continue
 		}
 		incidents = append(incidents, incident)
 	}
 
-	return incidents, nil
+	return incidents, hidden, nil
+}
+
+// ListIncidents fetches all the pages of incidents of the given options, whatever their suppression.
+func ListIncidents(c client.PagerDutyClient, opts pdApi.ListIncidentsOptions) ([]pdApi.Incident, error) {
+	var aerr pdApi.APIError
+	var incidents []pdApi.Incident
+
+	opts.Limit = incidentsPageSize
+	opts.Offset = 0
+
+	for {
+		response, err := c.ListIncidents(opts)
+
+		if err != nil {
+			if errors.As(err, &aerr) {
+				if aerr.RateLimited() {
+					return nil, fmt.Errorf("API rate limited")
+				}
+				return nil, fmt.Errorf("status code: %d, error: %s", aerr.StatusCode, err)
+			}
+
+			return nil, err
+		}
+
+		incidents = append(incidents, response.Incidents...)
+
+		if !response.More {
+			return incidents, nil
+		}
+
+		opts.Offset += opts.Limit
+	}
 }
 
 // GetIncidentAlerts returns all the alerts belonging to a particular incident.
+// Only the data of the triggered alerts is parsed.
 func GetIncidentAlerts(c client.PagerDutyClient, incident pdApi.Incident) ([]Alert, error) {
+	return incidentAlerts(c, incident, false)
+}
+
+// ParseIncidentAlerts returns all the alerts belonging to a particular incident with their data parsed, whatever their status.
+func ParseIncidentAlerts(c client.PagerDutyClient, incident pdApi.Incident) ([]Alert, error) {
+	return incidentAlerts(c, incident, true)
+}
+
+// incidentAlerts returns the alerts of the incident, parsing the data of all of them or only of the triggered ones.
+// The alerts without details, e.g. the ones created manually, are named after their summary.
+func incidentAlerts(c client.PagerDutyClient, incident pdApi.Incident, all bool) ([]Alert, error) {
 	var alerts []Alert
 
 	// Fetch alerts related to an incident via pagerduty API
@@ -87,12 +151,17 @@ func GetIncidentAlerts(c client.PagerDutyClient, incident pdApi.Incident) ([]Ale
 
 			return nil, fmt.Errorf("status code: %d, error: %s", aerr.StatusCode, err)
 		}
+
+		return nil, err
 	}
 
 	for _, alert := range incidentAlerts.Alerts {
 		status := alert.Status
 
 		tempAlertObj := Alert{}
+		tempAlertObj.ParseIncidentData(incident)
+		tempAlertObj.Body = alert.Body
+		tempAlertObj.Name = alert.Summary
 
 		// Fetch incident Urgency
 		tempAlertObj.Severity = incident.Urgency
@@ -101,13 +170,17 @@ func GetIncidentAlerts(c client.PagerDutyClient, incident pdApi.Incident) ([]Ale
 			tempAlertObj.Severity = alert.Severity
 		}
 
-		if status == constants.StatusTriggered {
+		_, hasDetails := alert.Body["details"].(map[string]interface{})
+
+		if hasDetails && (all || status == constants.StatusTriggered) {
 			err = tempAlertObj.ParseAlertData(c, &alert)
 
 			if err != nil {
 				return nil, err
 			}
+		}
 
+		if !all && status == constants.StatusTriggered {
 			TrigerredAlerts = append(TrigerredAlerts, tempAlertObj)
 		}
 
@@ -161,6 +234,79 @@ func AcknowledgeIncidents(c client.PagerDutyClient, incidentIDs []string) ([]pdA
 	return response.Incidents, nil
 }
 
+// DefaultResponderMessage is the message of the responder requests when none is given.
+const DefaultResponderMessage = "Please help with this incident"
+
+// RequestResponders requests the given users and escalation policies to respond to the incident.
+// The request is made on behalf of the current user.
+func RequestResponders(c client.PagerDutyClient, incidentID string, message string, targets []pdApi.APIObject) (*pdApi.ResponderRequestResponse, error) {
+	var opts client.ResponderRequestOptions
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("please select at least one responder")
+	}
+
+	user, err := c.GetCurrentUser(pdApi.GetCurrentUserOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts.From = user.Email
+	opts.RequesterID = user.ID
+	opts.Message = message
+
+	for _, target := range targets {
+		opts.Targets = append(opts.Targets, client.ResponderTarget{Target: pdApi.APIObject{ID: target.ID, Type: target.Type}})
+	}
+
+	return c.ResponderRequest(incidentID, opts)
+}
+
+// ParseIncidentData parses the pagerduty incident data an alert belongs to into the Alert struct.
+func (a *Alert) ParseIncidentData(incident pdApi.Incident) {
+	var assignees []string
+	var acknowledgers []string
+	var teams []string
+
+	a.IncidentID = incident.Id
+	a.IncidentTitle = incident.Title
+	a.IncidentStatus = incident.Status
+	a.CreatedAt = incident.CreatedAt
+	a.Urgency = incident.Urgency
+	a.Service = incident.Service.Summary
+	a.ServiceID = incident.Service.ID
+	a.EscalationPolicy = incident.EscalationPolicy.Summary
+	a.AlertCount = incident.AlertCounts.All
+	a.LastStatusChange = incident.LastStatusChangeAt
+
+	if incident.Priority != nil {
+		a.Priority = incident.Priority.Name
+
+		if a.Priority == "" {
+			a.Priority = incident.Priority.Summary
+		}
+	}
+
+	for _, assignment := range incident.Assignments {
+		assignees = append(assignees, assignment.Assignee.Summary)
+	}
+
+	a.Assignee = strings.Join(assignees, ", ")
+
+	for _, ack := range incident.Acknowledgements {
+		acknowledgers = append(acknowledgers, ack.Acknowledger.Summary)
+	}
+
+	a.Acknowledger = strings.Join(acknowledgers, ", ")
+
+	for _, team := range incident.Teams {
+		teams = append(teams, team.Summary)
+	}
+
+	a.Team = strings.Join(teams, ", ")
+}
+
 // ParseAlertData parses a pagerduty alert data into the Alert struct.
 func (a *Alert) ParseAlertData(c client.PagerDutyClient, alert *pdApi.IncidentAlert) (err error) {
 	a.IncidentID = alert.Incident.ID
@@ -168,6 +314,7 @@ func (a *Alert) ParseAlertData(c client.PagerDutyClient, alert *pdApi.IncidentAl
 	a.Name = alert.Summary
 	a.Status = alert.Status
 	a.WebURL = alert.HTMLURL
+	a.ServiceID = alert.Service.ID
 
 	// Check if the alert is of type 'Missing cluster'
 	isCHGM := alert.Body["details"].(map[string]interface{})["notes"]
@@ -181,13 +328,8 @@ func (a *Alert) ParseAlertData(c client.PagerDutyClient, alert *pdApi.IncidentAl
 		a.ClusterID = strings.Replace(notes[0], "cluster_id: ", "", 1)
 		a.ClusterName = strings.Split(fmt.Sprint(alert.Body["details"].(map[string]interface{})["name"]), ".")[0]
 
-		lastCheckIn := fmt.Sprint(alert.Body["details"].(map[string]interface{})["last healthy check-in"])
-		a.LastCheckIn, err = utils.FormatTimestamp(lastCheckIn)
-
-		if err != nil {
-			return err
-		}
-
+		// The PagerDuty timestamp is formatted when displayed
+		a.LastCheckIn = fmt.Sprint(alert.Body["details"].(map[string]interface{})["last healthy check-in"])
 		a.Token = fmt.Sprint(alert.Body["details"].(map[string]interface{})["token"])
 		a.Tags = fmt.Sprint(alert.Body["details"].(map[string]interface{})["tags"])
 		a.Sop = strings.Replace(notes[1], "runbook: ", "", 1)
@@ -251,7 +393,7 @@ func ParseAlertMetaData(alert Alert) string {
 	}
 
 	if alert.LastCheckIn != "" {
-		data := fmt.Sprintf("* Last Healthy Check-in: %s\n", alert.LastCheckIn)
+		data := fmt.Sprintf("* Last Healthy Check-in: %s\n", utils.DisplayTimestamp(alert.LastCheckIn))
 		alertData = alertData + data
 	}
 
@@ -282,85 +424,3 @@ func ParseAlertMetaData(alert Alert) string {
 
 	return alertData
 }
-
-// getTableData parses and returns tabular data for the given alerts, i.e table headers and rows.
-func GetTableData(alerts []Alert, cols string) ([]string, [][]string) {
-	var headers []string
-	var tableData [][]string
-
-	// columns returned by the columns flag
-	columns := strings.Split(cols, ",")
-
-	columnsMap := make(map[string]bool)
-
-	for _, c := range columns {
-		columnsMap[c] = true
-	}
-
-	headersMap := make(map[int]string)
-
-	for _, alert := range alerts {
-
-		var values []string
-
-		var i int
-
-		if columnsMap["incident.id"] {
-			i++
-			headersMap[i] = "INCIDENT ID"
-			values = append(values, alert.IncidentID)
-		}
-
-		if columnsMap["alert.id"] {
-			i++
-			headersMap[i] = "ALERT ID"
-			values = append(values, alert.AlertID)
-		}
-
-		if columnsMap["alert"] {
-			i++
-			headersMap[i] = "ALERT"
-			values = append(values, alert.Name)
-		}
-
-		if columnsMap["cluster.name"] {
-			i++
-			headersMap[i] = "CLUSTER NAME"
-			values = append(values, alert.ClusterName)
-		}
-
-		if columnsMap["cluster.id"] {
-			i++
-			headersMap[i] = "CLUSTER ID"
-			values = append(values, alert.ClusterID)
-		}
-
-		if columnsMap["status"] {
-			i++
-			headersMap[i] = "STATUS"
-			values = append(values, alert.Status)
-		}
-
-		if columnsMap["severity"] {
-			i++
-			headersMap[i] = "SEVERITY"
-			values = append(values, alert.Severity)
-		}
-
-		tableData = append(tableData, values)
-	}
-
-	keys := make([]int, 0)
-
-	for k := range headersMap {
-		keys = append(keys, k)
-	}
-
-	sort.Ints(keys)
-
-	for _, v := range keys {
-		headers = append(headers, headersMap[v])
-	}
-
-	return headers, tableData
-}
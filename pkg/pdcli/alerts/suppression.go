@@ -0,0 +1,92 @@
+package pdcli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+// SuppressionPolicy hides incidents which are noise for the team, e.g. the ones assigned to Silent Test.
+type SuppressionPolicy struct {
+	escalationPolicies []string
+	users              []string
+	services           []string
+	titles             []*regexp.Regexp
+}
+
+// IncidentSuppression is the suppression policy applied to the team incidents.
+var IncidentSuppression, _ = NewSuppressionPolicy(config.DefaultSuppression())
+
+// NewSuppressionPolicy returns a suppression policy for the given suppression config.
+func NewSuppressionPolicy(s config.Suppression) (*SuppressionPolicy, error) {
+	policy := &SuppressionPolicy{
+		escalationPolicies: s.EscalationPolicies,
+		users:              s.Users,
+		services:           s.Services,
+	}
+
+	for _, title := range s.Titles {
+		regex, err := regexp.Compile(title)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid suppressed title '%s': %v", title, err)
+		}
+
+		policy.titles = append(policy.titles, regex)
+	}
+
+	return policy, nil
+}
+
+// SetSuppression sets the suppression policy applied to the team incidents from the given config.
+func SetSuppression(cfg *config.Config) error {
+	policy, err := NewSuppressionPolicy(cfg.Suppression())
+
+	if err != nil {
+		return err
+	}
+
+	IncidentSuppression = policy
+
+	return nil
+}
+
+// Suppressed returns true if the incident is hidden by the suppression policy.
+func (p *SuppressionPolicy) Suppressed(incident pdApi.Incident) bool {
+	if p == nil {
+		return false
+	}
+
+	if matchesAPIObject(p.escalationPolicies, incident.EscalationPolicy) ||
+		matchesAPIObject(p.services, incident.Service) {
+		return true
+	}
+
+	for _, assignment := range incident.Assignments {
+		if matchesAPIObject(p.users, assignment.Assignee) {
+			return true
+		}
+	}
+
+	for _, title := range p.titles {
+		if title.MatchString(incident.Title) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesAPIObject returns true if the ID or the name of the PagerDuty object is in the given list.
+func matchesAPIObject(list []string, obj pdApi.APIObject) bool {
+	for _, item := range list {
+		if item == obj.ID || (obj.Summary != "" && strings.EqualFold(item, obj.Summary)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,207 @@
+package pdcli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// Filter matches alerts on the value of a column.
+type Filter struct {
+	Column   Column
+	Operator string
+	Value    string
+
+	regex *regexp.Regexp
+}
+
+// Filter operators, the first of them in an expression is its operator, '!=' is matched before '=' at the same index.
+var filterOperators = []string{"!=", "~", "="}
+
+// AssignmentOptions returns the incident options and the assignee name for the given assigned-to option.
+func AssignmentOptions(assignment string, user *pdApi.User, cfg *config.Config) (pdApi.ListIncidentsOptions, string, error) {
+	var opts pdApi.ListIncidentsOptions
+	var assignedTo string
+
+	opts.Urgencies = []string{constants.StatusLow, constants.StatusHigh}
+
+	switch assignment {
+
+	case "team":
+		if cfg == nil || len(cfg.Teams) == 0 {
+			return opts, "", fmt.Errorf("no team selected, please run 'kite teams' to set a team")
+		}
+
+		// Fetch incidents belonging to the selected teams
+		assignedTo = strings.Join(cfg.TeamNames(), ", ")
+		opts.TeamIDs = cfg.TeamIDs()
+		opts.Statuses = []string{constants.StatusTriggered, constants.StatusAcknowledged}
+
+	case "silentTest":
+		// Fetch incidents assigned to silent test
+		assignedTo = "Silent Test"
+		opts.UserIDs = []string{cfg.Organization().SilentTestUserID}
+		opts.Statuses = []string{constants.StatusTriggered, constants.StatusAcknowledged}
+
+	case "self":
+		// Fetch only acknowledged incidents assigned to self
+		assignedTo = user.Name
+		opts.UserIDs = []string{user.ID}
+		opts.Statuses = []string{constants.StatusAcknowledged}
+
+	default:
+		return opts, "", fmt.Errorf("please enter a valid assigned-to option")
+	}
+
+	return opts, assignedTo, nil
+}
+
+// ViewOptions returns the incident options and the assignee name for the given saved view and assigned-to option.
+// The assigned-to option is resolved by the caller, the view assignment doesn't take precedence over an explicit one.
+// The view statuses and urgencies override the defaults of the assigned-to option.
+func ViewOptions(view config.View, assignment string, user *pdApi.User, cfg *config.Config) (pdApi.ListIncidentsOptions, string, error) {
+	opts, assignedTo, err := AssignmentOptions(assignment, user, cfg)
+
+	if err != nil {
+		return opts, "", err
+	}
+
+	if len(view.Statuses) > 0 {
+		opts.Statuses = view.Statuses
+	}
+
+	if len(view.Urgencies) > 0 {
+		opts.Urgencies = view.Urgencies
+	}
+
+	return opts, assignedTo, nil
+}
+
+// ParseFilter parses a filter expression of the form <column>=<value>, <column>!=<value> or <column>~<regex>.
+// The column ends at the first operator, the value may contain operator characters, e.g. alert=foo~bar.
+func ParseFilter(expr string) (Filter, error) {
+	var filter Filter
+
+	index := -1
+
+	for _, op := range filterOperators {
+		i := strings.Index(expr, op)
+
+		if i >= 0 && (index < 0 || i < index) {
+			index = i
+			filter.Operator = op
+		}
+	}
+
+	if index <= 0 {
+		return filter, fmt.Errorf("invalid filter '%s', expected <column>=<value>, <column>!=<value> or <column>~<regex>", expr)
+	}
+
+	column, err := LookupColumn(expr[:index])
+
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Column = column
+	filter.Value = strings.TrimSpace(expr[index+len(filter.Operator):])
+
+	if filter.Operator == "~" {
+		filter.regex, err = regexp.Compile(filter.Value)
+
+		if err != nil {
+			return filter, fmt.Errorf("invalid filter '%s': %v", expr, err)
+		}
+	}
+
+	return filter, nil
+}
+
+// ParseFilters parses the given filter expressions.
+func ParseFilters(exprs []string) ([]Filter, error) {
+	var filters []Filter
+
+	for _, expr := range exprs {
+		filter, err := ParseFilter(expr)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// Match returns true if the alert matches the filter.
+func (f Filter) Match(a Alert) bool {
+	value := f.Column.Value(a)
+
+	switch f.Operator {
+	case "!=":
+		return !strings.EqualFold(value, f.Value)
+	case "~":
+		return f.regex.MatchString(value)
+	default:
+		return strings.EqualFold(value, f.Value)
+	}
+}
+
+// FilterAlerts returns the alerts matching all of the given filter expressions.
+func FilterAlerts(alerts []Alert, exprs []string) ([]Alert, error) {
+	var filtered []Alert
+
+	filters, err := ParseFilters(exprs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alert := range alerts {
+		matches := true
+
+		for _, f := range filters {
+			if !f.Match(alert) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			filtered = append(filtered, alert)
+		}
+	}
+
+	return filtered, nil
+}
+
+// SortAlerts sorts the alerts by the given column key, a '-' prefix sorts in descending order.
+func SortAlerts(alerts []Alert, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	descending := strings.HasPrefix(key, "-")
+
+	column, err := LookupColumn(strings.TrimPrefix(key, "-"))
+
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		if descending {
+			return column.SortValue(alerts[i]) > column.SortValue(alerts[j])
+		}
+
+		return column.SortValue(alerts[i]) < column.SortValue(alerts[j])
+	})
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package pdcli
+
+import (
+	"sort"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	alerts "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	oncall "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+)
+
+// LatestNotes is the number of notes of each incident included in the report.
+const LatestNotes = 3
+
+// Report is the handover report of a shift, the timestamps are PagerDuty timestamps.
+type Report struct {
+	Team        string     `json:"team"`
+	ShiftStart  time.Time  `json:"shift_start"`
+	ShiftEnd    time.Time  `json:"shift_end"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Incidents   []Incident `json:"incidents"`
+	Incoming    []Oncall   `json:"incoming_oncall"`
+}
+
+// Incident is an open incident of the team.
+type Incident struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Urgency   string   `json:"urgency"`
+	Service   string   `json:"service"`
+	Assignee  string   `json:"assignee"`
+	CreatedAt string   `json:"created_at"`
+	WebURL    string   `json:"web_url"`
+	Alerts    []string `json:"alerts"`
+	Notes     []Note   `json:"notes"`
+	Timeline  []Event  `json:"timeline"`
+}
+
+// Note is a note of an incident.
+type Note struct {
+	Author    string `json:"author"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Event is an action taken on an incident, e.g. an acknowledgement or an escalation.
+type Event struct {
+	At      string `json:"at"`
+	Agent   string `json:"agent"`
+	Summary string `json:"summary"`
+}
+
+// Oncall is a user of the incoming on-call layer.
+type Oncall struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// CurrentShift returns the start and end of the team on-call layer at the given time.
+func CurrentShift(c client.PagerDutyClient, org config.Org, now time.Time) (time.Time, time.Time, error) {
+	since, until := oncall.AroundWindow(now)
+
+	layers, err := oncall.TeamSREOnCall(c, org, since, until)
+
+	if err != nil {
+		return since, until, err
+	}
+
+	layer := layers[oncall.CurrentLayerIndex(layers, now)]
+
+	return layer.Start, layer.End, nil
+}
+
+// GenerateReport compiles the handover report of the acknowledged and triggered incidents of the team between the given times.
+// The incoming on-call is the team layer on-call at the end of the shift.
+func GenerateReport(c client.PagerDutyClient, cfg *config.Config, user *pdApi.User, since time.Time, until time.Time, now time.Time) (*Report, error) {
+	report := &Report{
+		ShiftStart:  since,
+		ShiftEnd:    until,
+		GeneratedAt: now,
+		Incidents:   []Incident{},
+		Incoming:    []Oncall{},
+	}
+
+	opts, team, err := alerts.AssignmentOptions("team", user, cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report.Team = team
+	opts.Statuses = []string{constants.StatusTriggered, constants.StatusAcknowledged}
+
+	incidents, err := alerts.GetIncidents(c, &opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, incident := range incidents {
+		reportIncident, err := incidentReport(c, incident, since, until)
+
+		if err != nil {
+			return nil, err
+		}
+
+		report.Incidents = append(report.Incidents, reportIncident)
+	}
+
+	// Triggered incidents are the ones pending, they are listed first
+	sort.SliceStable(report.Incidents, func(i, j int) bool {
+		return report.Incidents[i].Status == constants.StatusTriggered && report.Incidents[j].Status != constants.StatusTriggered
+	})
+
+	layers, err := oncall.TeamSREOnCall(c, cfg.Organization(), until, until.Add(time.Minute))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range layers[oncall.CurrentLayerIndex(layers, until)].Users {
+		report.Incoming = append(report.Incoming, Oncall{
+			Name:  user.Name,
+			Role:  user.OncallRole,
+			Start: user.Start,
+			End:   user.End,
+		})
+	}
+
+	return report, nil
+}
+
+// incidentReport returns the alert metadata, the latest notes and the timeline during the shift of the given incident.
+func incidentReport(c client.PagerDutyClient, incident pdApi.Incident, since time.Time, until time.Time) (Incident, error) {
+	var assignee string
+
+	if len(incident.Assignments) > 0 {
+		assignee = incident.Assignments[0].Assignee.Summary
+	}
+
+	report := Incident{
+		ID:        incident.Id,
+		Title:     incident.Title,
+		Status:    incident.Status,
+		Urgency:   incident.Urgency,
+		Service:   incident.Service.Summary,
+		Assignee:  assignee,
+		CreatedAt: incident.CreatedAt,
+		WebURL:    incident.HTMLURL,
+		Alerts:    []string{},
+		Notes:     []Note{},
+		Timeline:  []Event{},
+	}
+
+	incidentAlerts, err := alerts.GetIncidentAlerts(c, incident)
+
+	if err != nil {
+		return report, err
+	}
+
+	for _, alert := range incidentAlerts {
+		if metadata := alerts.ParseAlertMetaData(alert); metadata != "" {
+			report.Alerts = append(report.Alerts, metadata)
+		}
+	}
+
+	notes, err := c.ListIncidentNotes(incident.Id)
+
+	if err != nil {
+		return report, err
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedAt > notes[j].CreatedAt
+	})
+
+	for i, note := range notes {
+		if i == LatestNotes {
+			break
+		}
+
+		report.Notes = append(report.Notes, Note{Author: note.User.Summary, Content: note.Content, CreatedAt: note.CreatedAt})
+	}
+
+	logOpts := pdApi.ListIncidentLogEntriesOptions{
+		IsOverview: true,
+		Since:      since.Format(time.RFC3339),
+		Until:      until.Format(time.RFC3339),
+	}
+
+	logOpts.Limit = 100
+
+	for {
+		entries, err := c.ListIncidentLogEntries(incident.Id, logOpts)
+
+		if err != nil {
+			return report, err
+		}
+
+		for _, entry := range entries.LogEntries {
+			report.Timeline = append(report.Timeline, Event{At: entry.CreatedAt, Agent: entry.Agent.Summary, Summary: entry.Summary})
+		}
+
+		if !entries.More {
+			break
+		}
+
+		logOpts.Offset += logOpts.Limit
+	}
+
+	sort.SliceStable(report.Timeline, func(i, j int) bool {
+		return report.Timeline[i].At < report.Timeline[j].At
+	})
+
+	return report, nil
+}
@@ -0,0 +1,117 @@
+package pdcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+const (
+	// Handover report formats
+	MarkdownFormat = "md"
+	JSONFormat     = "json"
+)
+
+// Render renders the report in the given format.
+func Render(r *Report, format string) (string, error) {
+	switch format {
+	case MarkdownFormat, "markdown":
+		return Markdown(r), nil
+	case JSONFormat:
+		data, err := json.MarshalIndent(r, "", "  ")
+
+		if err != nil {
+			return "", err
+		}
+
+		return string(data) + "\n", nil
+	}
+
+	return "", fmt.Errorf("invalid format '%s', please use one of: %s, %s", format, MarkdownFormat, JSONFormat)
+}
+
+// Markdown renders the report as Markdown, the timestamps are formatted in the display timezone.
+func Markdown(r *Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Shift Handover - %s\n\n", r.Team)
+	fmt.Fprintf(&b, "* Shift: %s - %s\n", utils.FormatTime(r.ShiftStart), utils.FormatTime(r.ShiftEnd))
+	fmt.Fprintf(&b, "* Generated: %s\n", utils.FormatTime(r.GeneratedAt))
+
+	b.WriteString("\n## Incoming On-call\n\n")
+
+	if len(r.Incoming) == 0 {
+		b.WriteString("Nobody is on-call.\n")
+	}
+
+	for _, user := range r.Incoming {
+		fmt.Fprintf(&b, "* **%s** (%s) %s - %s\n", user.Name, user.Role, utils.DisplayTimestamp(user.Start), utils.DisplayTimestamp(user.End))
+	}
+
+	var triggered, acknowledged []Incident
+
+	for _, incident := range r.Incidents {
+		if incident.Status == constants.StatusTriggered {
+			triggered = append(triggered, incident)
+		} else {
+			acknowledged = append(acknowledged, incident)
+		}
+	}
+
+	writeIncidents(&b, "Pending - Triggered Incidents", triggered)
+	writeIncidents(&b, "In Progress - Acknowledged Incidents", acknowledged)
+
+	return b.String()
+}
+
+// writeIncidents writes a section of the report listing the given incidents.
+func writeIncidents(b *strings.Builder, title string, incidents []Incident) {
+	fmt.Fprintf(b, "\n## %s (%d)\n", title, len(incidents))
+
+	if len(incidents) == 0 {
+		b.WriteString("\nNone.\n")
+	}
+
+	for _, incident := range incidents {
+		fmt.Fprintf(b, "\n### [%s] %s\n\n", incident.ID, incident.Title)
+		fmt.Fprintf(b, "* Urgency: %s\n", incident.Urgency)
+		fmt.Fprintf(b, "* Service: %s\n", incident.Service)
+
+		if incident.Assignee != "" {
+			fmt.Fprintf(b, "* Assignee: %s\n", incident.Assignee)
+		}
+
+		fmt.Fprintf(b, "* Created: %s\n", utils.DisplayTimestamp(incident.CreatedAt))
+
+		if incident.WebURL != "" {
+			fmt.Fprintf(b, "* Link: %s\n", incident.WebURL)
+		}
+
+		if len(incident.Alerts) > 0 {
+			b.WriteString("\n#### Alerts\n")
+
+			for _, alert := range incident.Alerts {
+				fmt.Fprintf(b, "\n%s\n", strings.TrimRight(alert, "\n"))
+			}
+		}
+
+		if len(incident.Notes) > 0 {
+			b.WriteString("\n#### Latest Notes\n\n")
+
+			for _, note := range incident.Notes {
+				fmt.Fprintf(b, "* %s **%s**: %s\n", utils.DisplayTimestamp(note.CreatedAt), note.Author, note.Content)
+			}
+		}
+
+		if len(incident.Timeline) > 0 {
+			b.WriteString("\n#### Timeline\n\n")
+
+			for _, event := range incident.Timeline {
+				fmt.Fprintf(b, "* %s %s\n", utils.DisplayTimestamp(event.At), event.Summary)
+			}
+		}
+	}
+}
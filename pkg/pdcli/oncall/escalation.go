@@ -0,0 +1,159 @@
+package pdcli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+)
+
+// EscalationChain is the current on-call chain of the escalation policy of a service.
+type EscalationChain struct {
+	Service            string
+	ServiceID          string
+	EscalationPolicy   string
+	EscalationPolicyID string
+	Levels             []EscalationLevel
+}
+
+// EscalationLevel is a level of an escalation policy and the users currently on-call for it.
+type EscalationLevel struct {
+	Level   uint
+	Delay   uint
+	Targets []string
+	Users   []OncallUser
+}
+
+// serviceIDRegex matches PagerDuty object IDs.
+var serviceIDRegex = regexp.MustCompile(`^P[A-Z0-9]{6}$`)
+
+// ResolveService returns the PagerDuty service with the given ID or name.
+// Names are matched case-insensitively, a partial name is accepted if it matches a single service.
+func ResolveService(c client.PagerDutyClient, service string) (*pagerduty.Service, error) {
+	if serviceIDRegex.MatchString(service) {
+		s, err := c.GetService(service, &pagerduty.GetServiceOptions{})
+
+		if err == nil {
+			return s, nil
+		}
+	}
+
+	response, err := c.ListServices(pagerduty.ListServiceOptions{Query: service})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for i, s := range response.Services {
+		if strings.EqualFold(s.Name, service) {
+			return &response.Services[i], nil
+		}
+
+		names = append(names, s.Name)
+	}
+
+	switch len(response.Services) {
+	case 0:
+		return nil, fmt.Errorf("no PagerDuty service found matching '%s'", service)
+	case 1:
+		return &response.Services[0], nil
+	}
+
+	return nil, fmt.Errorf("multiple PagerDuty services match '%s': %s", service, strings.Join(names, ", "))
+}
+
+// ServiceEscalationChain resolves the given service ID or name to its escalation policy and returns its current on-call chain.
+func ServiceEscalationChain(c client.PagerDutyClient, service string) (*EscalationChain, error) {
+	s, err := ResolveService(c, service)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.EscalationPolicy.ID == "" {
+		return nil, fmt.Errorf("the service %s has no escalation policy", s.Name)
+	}
+
+	chain, err := PolicyEscalationChain(c, s.EscalationPolicy.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chain.Service = s.Name
+	chain.ServiceID = s.ID
+
+	if chain.Service == "" {
+		chain.Service = s.Summary
+	}
+
+	return chain, nil
+}
+
+// PolicyEscalationChain returns the users currently on-call for every level of the given escalation policy.
+func PolicyEscalationChain(c client.PagerDutyClient, policyID string) (*EscalationChain, error) {
+	var oncalls []pagerduty.OnCall
+
+	policy, err := c.GetEscalationPolicy(policyID, &pagerduty.GetEscalationPolicyOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	callOpts := pagerduty.ListOnCallOptions{EscalationPolicyIDs: []string{policyID}}
+	callOpts.Limit = 100
+
+	for {
+		oncallListing, err := c.ListOnCalls(callOpts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		oncalls = append(oncalls, oncallListing.OnCalls...)
+
+		if !oncallListing.More {
+			break
+		}
+
+		callOpts.Offset += callOpts.Limit
+	}
+
+	chain := &EscalationChain{
+		EscalationPolicy:   policy.Name,
+		EscalationPolicyID: policy.ID,
+	}
+
+	for i, rule := range policy.EscalationRules {
+		level := EscalationLevel{Level: uint(i + 1), Delay: rule.Delay}
+
+		for _, target := range rule.Targets {
+			level.Targets = append(level.Targets, target.Summary)
+		}
+
+		for _, y := range oncalls {
+			if y.EscalationLevel != level.Level {
+				continue
+			}
+
+			level.Users = append(level.Users, OncallUser{
+				EscalationPolicy:   y.EscalationPolicy.Summary,
+				EscalationPolicyID: y.EscalationPolicy.ID,
+				OncallRole:         y.Schedule.Summary,
+				ScheduleID:         y.Schedule.ID,
+				Name:               y.User.Summary,
+				UserID:             y.User.ID,
+				Start:              y.Start,
+				End:                y.End,
+			})
+		}
+
+		chain.Levels = append(chain.Levels, level)
+	}
+
+	return chain, nil
+}
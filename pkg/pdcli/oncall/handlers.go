@@ -1,119 +1,294 @@
 package pdcli
 
 import (
+	"fmt"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
-	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 )
 
+// OncallUser is a user on-call for a schedule, the start and end are PagerDuty timestamps.
 type OncallUser struct {
-	EscalationPolicy string
-	OncallRole       string
-	Name             string
-	Start            string
-	End              string
+	EscalationPolicy   string
+	EscalationPolicyID string
+	OncallRole         string
+	ScheduleID         string
+	Name               string
+	UserID             string
+	Start              string
+	End                string
 }
 
 type OncallLayer struct {
 	LayerId string
+	Start   time.Time
+	End     time.Time
 	Users   []OncallUser
 }
 
-// TeamSREOnCall fetches the current roles and names of on-call users.
-func TeamSREOnCall(c client.PagerDutyClient) ([]OncallLayer, error) {
-	var callOpts pagerduty.ListOnCallOptions
-	var oncallLayers []OncallLayer
+const (
+	// Default on-call window around the viewed time
+	windowBefore = time.Hour * 11
+	windowAfter  = time.Hour * 13
 
-	callOpts.ScheduleIDs = []string{
-		constants.PrimaryScheduleID,
-		constants.SecondaryScheduleID,
-		constants.OncallManager,
-		constants.OncallIDWeekend,
-		constants.InvestigatorID,
-	}
-	since := time.Now().Add(time.Hour * -11)
-	until := time.Now().Add(time.Hour * 13)
-	callOpts.Since = since.String()
-	callOpts.Until = until.String()
-	callOpts.Limit = 100
-	// Fetch the oncall data from pagerduty API
-	oncallListing, err := c.ListOnCalls(callOpts)
+	// Window of the next on-call shifts, PagerDuty lists the on-calls of at most 90 days
+	nextOncallWindow = time.Hour * 24 * 90
+)
 
-	if err != nil {
-		return nil, err
+// OncallWindow returns the on-call window and the time of the displayed layer for the given --at, --since and --until options.
+// The times are parsed with utils.ParseTime, the window defaults to the hours around now.
+func OncallWindow(at string, since string, until string, now time.Time) (time.Time, time.Time, time.Time, error) {
+	var start, end, viewed time.Time
+	var err error
+
+	if at != "" && (since != "" || until != "") {
+		return start, end, viewed, fmt.Errorf("the at option cannot be used with the since and until options")
 	}
 
-	startTime, _ := utils.FormatTimestamp(oncallListing.OnCalls[0].Start)
-	var temp []OncallUser
-	var mgmtUsers []OncallUser
+	if at != "" {
+		viewed, err = utils.ParseTime(at, now)
+
+		if err != nil {
+			return start, end, viewed, err
+		}
+
+		start, end = AroundWindow(viewed)
+
+		return start, end, viewed, nil
+	}
+
+	if since == "" && until == "" {
+		start, end = AroundWindow(now)
+
+		return start, end, now, nil
+	}
 
-	// OnCalls array contains all information about the API object
-	for ind, y := range oncallListing.OnCalls {
+	start = now
 
-		timeConversionStart, err := utils.FormatTimestamp(y.Start)
+	if since != "" {
+		start, err = utils.ParseTime(since, now)
+
+		if err != nil {
+			return start, end, viewed, err
+		}
+	}
+
+	end = start.Add(time.Hour * 24)
+
+	if until != "" {
+		end, err = utils.ParseTime(until, now)
+
+		if err != nil {
+			return start, end, viewed, err
+		}
+	}
+
+	if !end.After(start) {
+		return start, end, viewed, fmt.Errorf("the until time must be after the since time")
+	}
+
+	// Display the layer currently on-call if it is part of the window
+	viewed = start
+
+	if !now.Before(start) && now.Before(end) {
+		viewed = now
+	}
+
+	return start, end, viewed, nil
+}
+
+// AroundWindow returns the default on-call window around the given time.
+func AroundWindow(at time.Time) (time.Time, time.Time) {
+	return at.Add(-windowBefore), at.Add(windowAfter)
+}
+
+// TeamSREOnCall fetches the on-call layers of the org schedules between the given times.
+// The layers are the shifts of the primary and weekend schedules, the users on-call during each shift are added to its layer.
+func TeamSREOnCall(c client.PagerDutyClient, org config.Org, since time.Time, until time.Time) ([]OncallLayer, error) {
+	var callOpts pagerduty.ListOnCallOptions
+	var oncallLayers []OncallLayer
+	var oncalls []pagerduty.OnCall
+
+	// Fetch the shifts of the primary and weekend schedules
+	for _, scheduleID := range []string{org.PrimaryScheduleID, org.WeekendScheduleID} {
+		if scheduleID == "" {
+			continue
+		}
+
+		layers, err := scheduleLayers(c, scheduleID, org.LayerNames, since, until)
 
 		if err != nil {
 			return nil, err
 		}
 
-		timeConversionEnd, err := utils.FormatTimestamp(y.End)
+		oncallLayers = append(oncallLayers, layers...)
+	}
+
+	sort.SliceStable(oncallLayers, func(i, j int) bool {
+		return oncallLayers[i].Start.Before(oncallLayers[j].Start)
+	})
+
+	// Schedules without any shift in the given time are displayed as a single layer
+	if len(oncallLayers) == 0 {
+		oncallLayers = append(oncallLayers, OncallLayer{LayerId: "On-call", Start: since, End: until})
+	}
+
+	callOpts.ScheduleIDs = org.ScheduleIDs()
+	callOpts.Since = since.Format(time.RFC3339)
+	callOpts.Until = until.Format(time.RFC3339)
+	callOpts.Limit = 100
+
+	// Fetch the oncall data from pagerduty API
+	for {
+		oncallListing, err := c.ListOnCalls(callOpts)
 
 		if err != nil {
 			return nil, err
 		}
 
-		// Parse the oncall data to OncallUser object
-		if strings.Contains(y.Schedule.Summary, "Management") {
+		oncalls = append(oncalls, oncallListing.OnCalls...)
+
+		if !oncallListing.More {
+			break
+		}
+
+		callOpts.Offset += callOpts.Limit
+	}
+
+	// Add the users on-call during each layer, e.g. the manager on-call is part of every layer
+	for i := range oncallLayers {
+		for _, y := range oncalls {
+			start, err := utils.ParseTimestamp(y.Start)
+
+			if err != nil {
+				return nil, err
+			}
+
+			end, err := utils.ParseTimestamp(y.End)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if !start.Before(oncallLayers[i].End) || !end.After(oncallLayers[i].Start) {
+				continue
+			}
+
+			// Parse the oncall data to OncallUser object, the timestamps are formatted when displayed
 			tempUser := OncallUser{}
 			tempUser.EscalationPolicy = y.EscalationPolicy.Summary
+			tempUser.EscalationPolicyID = y.EscalationPolicy.ID
 			tempUser.OncallRole = y.Schedule.Summary
+			tempUser.ScheduleID = y.Schedule.ID
 			tempUser.Name = y.User.Summary
-			tempUser.Start = timeConversionStart
-			tempUser.End = timeConversionEnd
-			mgmtUsers = append(mgmtUsers, tempUser)
-			startTime, _ = utils.FormatTimestamp(oncallListing.OnCalls[ind+1].Start)
-			continue
+			tempUser.UserID = y.User.ID
+			tempUser.Start = y.Start
+			tempUser.End = y.End
+			oncallLayers[i].Users = append(oncallLayers[i].Users, tempUser)
 		}
+	}
+
+	return oncallLayers, nil
+}
 
-		if timeConversionStart != startTime {
-			temp = append(temp, mgmtUsers...)
-			oncallStartTime := startTime[11:16]
-			var layerId string
-			switch oncallStartTime {
-			case "22:30":
-				layerId = "Layer 1 [ APAC-E ]"
-			case "03:30":
-				layerId = "Layer 2 [ APAC-W ]"
-			case "08:30":
-				layerId = "Layer 3 [ EMEA ]"
-			case "13:30":
-				layerId = "Layer 4 [ NASA-E ]"
-			case "18:00":
-				layerId = "Layer 5 [ NASA-W ]"
+// CurrentLayerIndex returns the index of the layer on-call at the given time.
+// If no layer is on-call, the index of the next layer is returned, or the last layer if all of them have ended.
+func CurrentLayerIndex(layers []OncallLayer, at time.Time) int {
+	for i, layer := range layers {
+		if !at.Before(layer.Start) && at.Before(layer.End) {
+			return i
+		}
+	}
+
+	for i, layer := range layers {
+		if layer.Start.After(at) {
+			return i
+		}
+	}
+
+	if len(layers) == 0 {
+		return 0
+	}
+
+	return len(layers) - 1
+}
+
+// scheduleLayers returns a layer for each final schedule entry of the given schedule.
+// Each layer is named after the schedule layer which is on-call at its start time, or its configured name.
+func scheduleLayers(c client.PagerDutyClient, scheduleID string, layerNames map[string]string, since time.Time, until time.Time) ([]OncallLayer, error) {
+	var layers []OncallLayer
+
+	schedule, err := c.GetSchedule(scheduleID, pagerduty.GetScheduleOptions{
+		TimeZone: "UTC",
+		Since:    since.Format(time.RFC3339),
+		Until:    until.Format(time.RFC3339),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range schedule.FinalSchedule.RenderedScheduleEntries {
+		start, err := utils.ParseTimestamp(entry.Start)
+
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := utils.ParseTimestamp(entry.End)
+
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, OncallLayer{
+			LayerId: layerName(schedule, start, layerNames),
+			Start:   start,
+			End:     end,
+		})
+	}
+
+	return layers, nil
+}
+
+// layerName returns the name of the schedule layer on-call at the given time.
+// The configured layer names are looked up by the schedule layer ID or name.
+func layerName(schedule *pagerduty.Schedule, at time.Time, layerNames map[string]string) string {
+	for i, layer := range schedule.ScheduleLayers {
+		for _, entry := range layer.RenderedScheduleEntries {
+			start, err := utils.ParseTimestamp(entry.Start)
+
+			if err != nil {
+				continue
 			}
-			oncallLayer := &OncallLayer{
-				LayerId: layerId,
-				Users:   temp,
+
+			end, err := utils.ParseTimestamp(entry.End)
+
+			if err != nil || at.Before(start) || !at.Before(end) {
+				continue
+			}
+
+			if name, ok := layerNames[layer.ID]; ok {
+				return name
+			}
+
+			if name, ok := layerNames[layer.Name]; ok {
+				return name
+			}
+
+			if layer.Name != "" {
+				return layer.Name
 			}
-			oncallLayers = append(oncallLayers, *oncallLayer)
-			temp = nil
-			startTime = timeConversionStart
-		}
 
-		tempUser := OncallUser{}
-		tempUser.EscalationPolicy = y.EscalationPolicy.Summary
-		tempUser.OncallRole = y.Schedule.Summary
-		tempUser.Name = y.User.Summary
-		tempUser.Start = timeConversionStart
-		tempUser.End = timeConversionEnd
-		temp = append(temp, tempUser)
+			return fmt.Sprintf("Layer %d", len(schedule.ScheduleLayers)-i)
+		}
 	}
-	return oncallLayers, err
+
+	return schedule.Name
 }
 
 // AllTeamsOncall displays the oncall data of all Red Hat PagerDuty teams.
@@ -142,8 +317,11 @@ func AllTeamsOncall(c client.PagerDutyClient) ([]OncallUser, error) {
 		for _, y := range oncallListing.OnCalls {
 			temp := OncallUser{}
 			temp.EscalationPolicy = y.EscalationPolicy.Summary
+			temp.EscalationPolicyID = y.EscalationPolicy.ID
 			temp.OncallRole = y.Schedule.Summary
+			temp.ScheduleID = y.Schedule.ID
 			temp.Name = y.User.Summary
+			temp.UserID = y.User.ID
 			temp.Start = y.Start
 			temp.End = y.End
 			oncallData = append(oncallData, temp)
@@ -159,43 +337,42 @@ func AllTeamsOncall(c client.PagerDutyClient) ([]OncallUser, error) {
 }
 
 // UserNextOncallSchedule displays the current user's
-// next oncall schedule.
-func UserNextOncallSchedule(c client.PagerDutyClient, userID string) ([]OncallUser, error) {
+// next oncall schedule, during the next on-call window.
+func UserNextOncallSchedule(c client.PagerDutyClient, userID string, now time.Time) ([]OncallUser, error) {
 	var callOpts pagerduty.ListOnCallOptions
 	var nextOncallData []OncallUser
 
-	callOpts.Until = time.Now().AddDate(0, 3, 0).String()
-
+	callOpts.Since = now.Format(time.RFC3339)
+	callOpts.Until = now.Add(nextOncallWindow).Format(time.RFC3339)
 	callOpts.UserIDs = append(callOpts.UserIDs, userID)
+	callOpts.Limit = 100
 
 	// Fetch the oncall data from pagerduty API
-	onCallOncallUser, err := c.ListOnCalls(callOpts)
-
-	if err != nil {
-		return nil, err
-	}
-
-	for _, y := range onCallOncallUser.OnCalls {
-
-		start, err := utils.FormatTimestamp(y.Start)
+	for {
+		onCallOncallUser, err := c.ListOnCalls(callOpts)
 
 		if err != nil {
 			return nil, err
 		}
 
-		end, err := utils.FormatTimestamp(y.End)
+		for _, y := range onCallOncallUser.OnCalls {
+			temp := OncallUser{}
+			temp.EscalationPolicy = y.EscalationPolicy.Summary
+			temp.EscalationPolicyID = y.EscalationPolicy.ID
+			temp.OncallRole = y.Schedule.Summary
+			temp.ScheduleID = y.Schedule.ID
+			temp.Name = y.User.Summary
+			temp.UserID = y.User.ID
+			temp.Start = y.Start
+			temp.End = y.End
+			nextOncallData = append(nextOncallData, temp)
+		}
 
-		if err != nil {
-			return nil, err
+		if !onCallOncallUser.More {
+			break
 		}
 
-		temp := OncallUser{}
-		temp.EscalationPolicy = y.EscalationPolicy.Summary
-		temp.OncallRole = y.Schedule.Summary
-		temp.Name = y.User.Summary
-		temp.Start = start
-		temp.End = end
-		nextOncallData = append(nextOncallData, temp)
+		callOpts.Offset += callOpts.Limit
 	}
 
 	return nextOncallData, nil
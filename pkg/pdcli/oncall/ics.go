@@ -0,0 +1,123 @@
+package pdcli
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+const (
+	icsTimeFormat = "20060102T150405Z"
+
+	// Maximum length in octets of a content line, longer lines are folded
+	icsLineLength = 75
+)
+
+// icsEvent is an on-call shift with a valid start and end.
+type icsEvent struct {
+	shift OncallUser
+	start time.Time
+	end   time.Time
+}
+
+// icsEscaper escapes the special characters of an iCalendar text value.
+var icsEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// ICS returns the given on-call shifts as an RFC 5545 iCalendar, with one event per shift.
+// The shifts without a valid start and end, e.g. permanent on-call entries, are skipped.
+// The event UIDs are derived from the user, schedule and escalation policy of each shift and its rank among their shifts,
+// so that calendar applications update the events of a re-exported calendar, including the moved shifts, rather than duplicating them.
+func ICS(shifts []OncallUser, now time.Time) string {
+	var b strings.Builder
+
+	var events []icsEvent
+
+	for _, shift := range shifts {
+		start, startErr := utils.ParseTimestamp(shift.Start)
+		end, endErr := utils.ParseTimestamp(shift.End)
+
+		// The permanent on-call entries have no start or end, they are not shifts of the calendar
+		if startErr != nil || endErr != nil {
+			continue
+		}
+
+		events = append(events, icsEvent{shift: shift, start: start, end: end})
+	}
+
+	// The shifts are ranked by start, the rank of a shift doesn't change when it is moved
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].start.Before(events[j].start)
+	})
+
+	ranks := make(map[string]int)
+
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Red Hat//kite//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:PagerDuty On-call")
+
+	for _, event := range events {
+		shift := event.shift
+		key := shiftKey(shift)
+		ranks[key]++
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+shiftUID(key, ranks[key]))
+		writeICSLine(&b, "DTSTAMP:"+now.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "DTSTART:"+event.start.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "DTEND:"+event.end.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "SUMMARY:"+icsEscaper.Replace("On-call: "+shift.OncallRole))
+		writeICSLine(&b, "DESCRIPTION:"+icsEscaper.Replace(fmt.Sprintf("Role: %s\nEscalation policy: %s\nUser: %s",
+			shift.OncallRole,
+			shift.EscalationPolicy,
+			shift.Name)))
+		writeICSLine(&b, "CATEGORIES:"+icsEscaper.Replace(shift.EscalationPolicy))
+		writeICSLine(&b, "TRANSP:OPAQUE")
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+
+	return b.String()
+}
+
+// shiftKey returns the key of the on-call shifts of a user for the same schedule and escalation policy.
+func shiftKey(shift OncallUser) string {
+	return strings.Join([]string{
+		shift.UserID,
+		shift.ScheduleID,
+		shift.EscalationPolicyID,
+	}, "|")
+}
+
+// shiftUID returns a stable UID of the on-call shift of the given key and rank.
+func shiftUID(key string, rank int) string {
+	return fmt.Sprintf("%x@kite", sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, rank))))
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it into lines of at most 75 octets.
+// The folded lines start with a space, which is part of their length.
+func writeICSLine(b *strings.Builder, line string) {
+	length := icsLineLength
+
+	for len(line) > length {
+		cut := length
+
+		// Do not split a multi-byte UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		length = icsLineLength - 1
+	}
+
+	b.WriteString(line + "\r\n")
+}
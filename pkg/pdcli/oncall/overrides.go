@@ -0,0 +1,313 @@
+package pdcli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+// Override is an override of an on-call schedule, the start and end are PagerDuty timestamps.
+type Override struct {
+	ID         string
+	ScheduleID string
+	Schedule   string
+	User       string
+	UserID     string
+	Start      string
+	End        string
+}
+
+// Gap is a time range of a schedule without any user on-call.
+type Gap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ScheduleRoles returns the configured schedule IDs of the org by on-call role, in display order.
+func ScheduleRoles(org config.Org) ([]string, map[string]string) {
+	roles := []string{"primary", "secondary", "manager", "weekend", "investigator"}
+	ids := map[string]string{
+		"primary":      org.PrimaryScheduleID,
+		"secondary":    org.SecondaryScheduleID,
+		"manager":      org.ManagerScheduleID,
+		"weekend":      org.WeekendScheduleID,
+		"investigator": org.InvestigatorScheduleID,
+	}
+
+	var configured []string
+
+	for _, role := range roles {
+		if ids[role] != "" {
+			configured = append(configured, role)
+		}
+	}
+
+	return configured, ids
+}
+
+// ResolveSchedule returns the ID of the configured schedule matching the given role or ID.
+// Only the schedules configured in the org profile can be overridden.
+func ResolveSchedule(org config.Org, schedule string) (string, error) {
+	roles, ids := ScheduleRoles(org)
+
+	for _, role := range roles {
+		if strings.EqualFold(schedule, role) || schedule == ids[role] {
+			return ids[role], nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown schedule '%s', please use one of: %s", schedule, strings.Join(roles, ", "))
+}
+
+// scheduleRole returns the on-call role of the given schedule ID, or the ID if it is not configured.
+func scheduleRole(org config.Org, scheduleID string) string {
+	roles, ids := ScheduleRoles(org)
+
+	for _, role := range roles {
+		if ids[role] == scheduleID {
+			return role
+		}
+	}
+
+	return scheduleID
+}
+
+// ResolveUser returns the ID of the PagerDuty user matching the given ID or email.
+func ResolveUser(c client.PagerDutyClient, user string) (string, error) {
+	if !strings.Contains(user, "@") {
+		return user, nil
+	}
+
+	users, err := c.ListUsers(pagerduty.ListUsersOptions{Query: user})
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, u := range users.Users {
+		if strings.EqualFold(u.Email, user) {
+			return u.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no PagerDuty user found with the email %s", user)
+}
+
+// ListOverrides fetches the overrides of the given schedules between the given times, sorted by start time.
+func ListOverrides(c client.PagerDutyClient, org config.Org, scheduleIDs []string, since time.Time, until time.Time) ([]Override, error) {
+	var overrides []Override
+	var starts []time.Time
+
+	for _, scheduleID := range scheduleIDs {
+		opts := pagerduty.ListOverridesOptions{
+			Since: since.Format(time.RFC3339),
+			Until: until.Format(time.RFC3339),
+		}
+
+		opts.Limit = 100
+
+		for {
+			response, err := c.ListOverrides(scheduleID, opts)
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, o := range response.Overrides {
+				// The timestamps of the schedules may have different offsets, they are sorted once parsed
+				start, err := utils.ParseTimestamp(o.Start)
+
+				if err != nil {
+					return nil, err
+				}
+
+				starts = append(starts, start)
+				overrides = append(overrides, Override{
+					ID:         o.ID,
+					ScheduleID: scheduleID,
+					Schedule:   scheduleRole(org, scheduleID),
+					User:       o.User.Summary,
+					UserID:     o.User.ID,
+					Start:      o.Start,
+					End:        o.End,
+				})
+			}
+
+			if !response.More {
+				break
+			}
+
+			opts.Offset += opts.Limit
+		}
+	}
+
+	sort.Stable(byStart{overrides, starts})
+
+	return overrides, nil
+}
+
+// byStart sorts the overrides by their parsed start times.
+type byStart struct {
+	overrides []Override
+	starts    []time.Time
+}
+
+func (b byStart) Len() int { return len(b.overrides) }
+
+func (b byStart) Less(i, j int) bool { return b.starts[i].Before(b.starts[j]) }
+
+func (b byStart) Swap(i, j int) {
+	b.overrides[i], b.overrides[j] = b.overrides[j], b.overrides[i]
+	b.starts[i], b.starts[j] = b.starts[j], b.starts[i]
+}
+
+// CreateOverride overrides the given schedule with the given user between the given times.
+func CreateOverride(c client.PagerDutyClient, scheduleID string, userID string, start time.Time, end time.Time) (*Override, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("please specify the user of the override")
+	}
+
+	if !end.After(start) {
+		return nil, fmt.Errorf("the end of the override must be after its start")
+	}
+
+	override, err := c.CreateOverride(scheduleID, pagerduty.Override{
+		Start: start.Format(time.RFC3339),
+		End:   end.Format(time.RFC3339),
+		User:  pagerduty.APIObject{ID: userID, Type: "user_reference"},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Override{
+		ID:         override.ID,
+		ScheduleID: scheduleID,
+		User:       override.User.Summary,
+		UserID:     override.User.ID,
+		Start:      override.Start,
+		End:        override.End,
+	}, nil
+}
+
+// DeleteOverride deletes the given override of a schedule.
+// The override is not deleted if the schedule layers leave a gap in its time range, unless force is set.
+func DeleteOverride(c client.PagerDutyClient, override Override, force bool) error {
+	if !force {
+		start, err := utils.ParseTimestamp(override.Start)
+
+		if err != nil {
+			return err
+		}
+
+		end, err := utils.ParseTimestamp(override.End)
+
+		if err != nil {
+			return err
+		}
+
+		schedule, err := c.GetSchedule(override.ScheduleID, pagerduty.GetScheduleOptions{
+			TimeZone: "UTC",
+			Since:    start.Format(time.RFC3339),
+			Until:    end.Format(time.RFC3339),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		gaps, err := CoverageGaps(schedule, start, end)
+
+		if err != nil {
+			return err
+		}
+
+		if len(gaps) > 0 {
+			return fmt.Errorf("deleting the override leaves nobody on-call from %s to %s, use --force to delete it anyway",
+				utils.FormatTime(gaps[0].Start), utils.FormatTime(gaps[0].End))
+		}
+	}
+
+	return c.DeleteOverride(override.ScheduleID, override.ID)
+}
+
+// FindOverride returns the override with the given ID of the given schedules between the given times.
+func FindOverride(c client.PagerDutyClient, org config.Org, scheduleIDs []string, overrideID string, since time.Time, until time.Time) (Override, error) {
+	overrides, err := ListOverrides(c, org, scheduleIDs, since, until)
+
+	if err != nil {
+		return Override{}, err
+	}
+
+	for _, override := range overrides {
+		if override.ID == overrideID {
+			return override, nil
+		}
+	}
+
+	return Override{}, fmt.Errorf("override %s not found", overrideID)
+}
+
+// CoverageGaps returns the time ranges between start and end not covered by any layer of the schedule.
+// Overrides are not part of the schedule layers, so the gaps are the ones left once the overrides are removed.
+func CoverageGaps(schedule *pagerduty.Schedule, start time.Time, end time.Time) ([]Gap, error) {
+	var covered []Gap
+	var gaps []Gap
+
+	for _, layer := range schedule.ScheduleLayers {
+		for _, entry := range layer.RenderedScheduleEntries {
+			entryStart, err := utils.ParseTimestamp(entry.Start)
+
+			if err != nil {
+				return nil, err
+			}
+
+			entryEnd, err := utils.ParseTimestamp(entry.End)
+
+			if err != nil {
+				return nil, err
+			}
+
+			covered = append(covered, Gap{Start: entryStart, End: entryEnd})
+		}
+	}
+
+	sort.Slice(covered, func(i, j int) bool {
+		return covered[i].Start.Before(covered[j].Start)
+	})
+
+	cursor := start
+
+	for _, r := range covered {
+		if !cursor.Before(end) {
+			break
+		}
+
+		if r.Start.After(cursor) {
+			gapEnd := r.Start
+
+			if gapEnd.After(end) {
+				gapEnd = end
+			}
+
+			gaps = append(gaps, Gap{Start: cursor, End: gapEnd})
+		}
+
+		if r.End.After(cursor) {
+			cursor = r.End
+		}
+	}
+
+	if cursor.Before(end) {
+		gaps = append(gaps, Gap{Start: cursor, End: end})
+	}
+
+	return gaps, nil
+}
@@ -0,0 +1,100 @@
+package pdcli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+const (
+	// Types of the responder request targets
+	UserTarget             = "user_reference"
+	EscalationPolicyTarget = "escalation_policy_reference"
+)
+
+// ResponderTarget is a user or an escalation policy which can be requested to respond to an incident.
+type ResponderTarget struct {
+	ID   string
+	Type string
+	Name string
+	Role string
+}
+
+// Reference returns the PagerDuty reference of the target.
+func (t ResponderTarget) Reference() pagerduty.APIObject {
+	return pagerduty.APIObject{ID: t.ID, Type: t.Type}
+}
+
+// ResponderTargets returns the responder request targets of the given on-call data.
+// The users of the team layer on-call at the given time come first, followed by the escalation policies of all the teams sorted by name.
+func ResponderTargets(layers []OncallLayer, allTeams []OncallUser, at time.Time) []ResponderTarget {
+	var targets []ResponderTarget
+	var policies []ResponderTarget
+
+	seen := make(map[string]bool)
+
+	if len(layers) > 0 {
+		for _, user := range layers[CurrentLayerIndex(layers, at)].Users {
+			if user.UserID == "" || seen[user.UserID] {
+				continue
+			}
+
+			seen[user.UserID] = true
+			targets = append(targets, ResponderTarget{ID: user.UserID, Type: UserTarget, Name: user.Name, Role: user.OncallRole})
+		}
+	}
+
+	for _, layer := range layers {
+		allTeams = append(allTeams, layer.Users...)
+	}
+
+	for _, user := range allTeams {
+		if user.EscalationPolicyID == "" || seen[user.EscalationPolicyID] {
+			continue
+		}
+
+		seen[user.EscalationPolicyID] = true
+		policies = append(policies, ResponderTarget{ID: user.EscalationPolicyID, Type: EscalationPolicyTarget, Name: user.EscalationPolicy, Role: "Escalation Policy"})
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+
+	return append(targets, policies...)
+}
+
+// OncallResponderTargets fetches the current on-call data of the team and of all the teams, and returns its responder request targets.
+func OncallResponderTargets(c client.PagerDutyClient, org config.Org, now time.Time) ([]ResponderTarget, error) {
+	since, until := AroundWindow(now)
+
+	layers, err := TeamSREOnCall(c, org, since, until)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allTeams, err := AllTeamsOncall(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ResponderTargets(layers, allTeams, now), nil
+}
+
+// FindResponderTarget returns the target with the given ID or name.
+func FindResponderTarget(targets []ResponderTarget, target string) (ResponderTarget, error) {
+	for _, t := range targets {
+		if t.ID == target || strings.EqualFold(t.Name, target) {
+			return t, nil
+		}
+	}
+
+	return ResponderTarget{}, fmt.Errorf("'%s' is not a user or an escalation policy currently on-call", target)
+}
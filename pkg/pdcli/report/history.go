@@ -0,0 +1,270 @@
+package pdcli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	alerts "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+// ClusterHistory lists the recent incidents of a cluster, the latest first.
+type ClusterHistory struct {
+	ClusterID   string
+	ClusterName string
+	Since       time.Time
+	Until       time.Time
+	Incidents   []ClusterIncident
+}
+
+// ClusterIncident is an incident of the cluster history, the timestamps are PagerDuty timestamps.
+type ClusterIncident struct {
+	ID         string
+	Title      string
+	Status     string
+	CreatedAt  string
+	ResolvedAt string
+	Alerts     []string
+	Sops       []string
+	Notes      []Note
+}
+
+// Note is a note of an incident.
+type Note struct {
+	Author    string
+	Content   string
+	CreatedAt string
+}
+
+// ClusterIncidentHistory searches the incidents of the teams created during the history window for the cluster of the given alert, whatever their status.
+// The incidents are narrowed to the service of the alert when it is known, the incident of the alert itself is not included.
+func ClusterIncidentHistory(c client.PagerDutyClient, cfg *config.Config, user *pdApi.User, alert alerts.Alert, now time.Time) (*ClusterHistory, error) {
+	clusterID := knownValue(alert.ClusterID)
+	clusterName := knownValue(alert.ClusterName)
+
+	if clusterID == "" && clusterName == "" {
+		return nil, fmt.Errorf("no cluster associated with the alert")
+	}
+
+	history := &ClusterHistory{
+		ClusterID:   clusterID,
+		ClusterName: clusterName,
+		Since:       now.Add(-cfg.HistoryWindow()),
+		Until:       now,
+	}
+
+	opts, _, err := alerts.AssignmentOptions("team", user, cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The alerts of every incident are fetched to match the cluster, only the incidents of the service of the cluster are candidates
+	if serviceID := knownValue(alert.ServiceID); serviceID != "" {
+		opts.ServiceIDs = []string{serviceID}
+	}
+
+	incidents, err := ListIncidents(c, opts, history.Since, history.Until)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, incident := range incidents {
+		if incident.Id == alert.IncidentID {
+			continue
+		}
+
+		clusterIncident, found, err := clusterIncident(c, incident, clusterID, clusterName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			history.Incidents = append(history.Incidents, clusterIncident)
+		}
+	}
+
+	sort.SliceStable(history.Incidents, func(i, j int) bool {
+		return history.Incidents[i].CreatedAt > history.Incidents[j].CreatedAt
+	})
+
+	return history, nil
+}
+
+// Frequency returns the number of incidents of each alert name.
+func (h *ClusterHistory) Frequency() []Count {
+	counts := make(map[string]int)
+
+	for _, incident := range h.Incidents {
+		for _, name := range unique(incident.Alerts) {
+			counts[name]++
+		}
+	}
+
+	return sortedCounts(counts)
+}
+
+// LastOccurrence returns the creation timestamp of the latest incident, or an empty string if there are none.
+func (h *ClusterHistory) LastOccurrence() string {
+	if len(h.Incidents) == 0 {
+		return ""
+	}
+
+	return h.Incidents[0].CreatedAt
+}
+
+// ParseClusterHistory parses the cluster history into a string and returns it.
+func ParseClusterHistory(h *ClusterHistory) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "* Cluster ID: %s\n", naValue(h.ClusterID))
+	fmt.Fprintf(&b, "* Cluster Name: %s\n", naValue(h.ClusterName))
+	fmt.Fprintf(&b, "* Window: %s - %s\n", utils.FormatTime(h.Since), utils.FormatTime(h.Until))
+	fmt.Fprintf(&b, "* Incidents: %d\n", len(h.Incidents))
+
+	if len(h.Incidents) == 0 {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "* Last Occurrence: %s\n", utils.DisplayTimestamp(h.LastOccurrence()))
+
+	b.WriteString("\nFrequency\n\n")
+
+	for _, count := range h.Frequency() {
+		fmt.Fprintf(&b, "* %dx %s\n", count.Count, count.Key)
+	}
+
+	b.WriteString("\nIncidents\n")
+
+	for _, incident := range h.Incidents {
+		fmt.Fprintf(&b, "\n[%s] %s\n", incident.ID, incident.Title)
+		fmt.Fprintf(&b, "* Status: %s\n", incident.Status)
+		fmt.Fprintf(&b, "* Created: %s\n", utils.DisplayTimestamp(incident.CreatedAt))
+
+		if incident.ResolvedAt != "" {
+			fmt.Fprintf(&b, "* Resolved: %s\n", utils.DisplayTimestamp(incident.ResolvedAt))
+		}
+
+		fmt.Fprintf(&b, "* Alerts: %s\n", strings.Join(unique(incident.Alerts), ", "))
+
+		if len(incident.Sops) == 0 {
+			b.WriteString("* SOP: N/A\n")
+		}
+
+		for _, sop := range incident.Sops {
+			fmt.Fprintf(&b, "* SOP: %s\n", sop)
+		}
+
+		for _, note := range incident.Notes {
+			fmt.Fprintf(&b, "* Note %s %s: %s\n", utils.DisplayTimestamp(note.CreatedAt), note.Author, note.Content)
+		}
+	}
+
+	return b.String()
+}
+
+// clusterIncident returns the alerts, SOPs and notes of the incident if one of its alerts is raised for the given cluster.
+func clusterIncident(c client.PagerDutyClient, incident pdApi.Incident, clusterID string, clusterName string) (ClusterIncident, bool, error) {
+	found := ClusterIncident{
+		ID:        incident.Id,
+		Title:     incident.Title,
+		Status:    incident.Status,
+		CreatedAt: incident.CreatedAt,
+	}
+
+	incidentAlerts, err := alerts.ParseIncidentAlerts(c, incident)
+
+	if err != nil {
+		return found, false, err
+	}
+
+	for _, alert := range incidentAlerts {
+		if !matchesCluster(alert.Body, clusterID, clusterName) {
+			continue
+		}
+
+		found.Alerts = append(found.Alerts, alert.Name)
+
+		if sop := knownValue(alert.Sop); sop != "" {
+			found.Sops = append(found.Sops, sop)
+		}
+	}
+
+	if len(found.Alerts) == 0 {
+		return found, false, nil
+	}
+
+	found.Sops = unique(found.Sops)
+
+	if incident.Status == constants.StatusResolved {
+		found.ResolvedAt = incident.LastStatusChangeAt
+	}
+
+	notes, err := c.ListIncidentNotes(incident.Id)
+
+	if err != nil {
+		return found, false, err
+	}
+
+	for _, note := range notes {
+		found.Notes = append(found.Notes, Note{Author: note.User.Summary, Content: note.Content, CreatedAt: note.CreatedAt})
+	}
+
+	sort.SliceStable(found.Notes, func(i, j int) bool {
+		return found.Notes[i].CreatedAt < found.Notes[j].CreatedAt
+	})
+
+	return found, true, nil
+}
+
+// matchesCluster checks whether the details of the alert body reference the given cluster.
+// The cluster ID is the one of the alert details or of the 'Missing cluster' alert notes, the cluster name the one of the 'Missing cluster' alerts.
+func matchesCluster(body map[string]interface{}, clusterID string, clusterName string) bool {
+	details, ok := body["details"].(map[string]interface{})
+
+	if !ok {
+		return false
+	}
+
+	if clusterID != "" {
+		if fmt.Sprint(details["cluster_id"]) == clusterID {
+			return true
+		}
+
+		if notes, ok := details["notes"]; ok && strings.Contains(fmt.Sprint(notes), "cluster_id: "+clusterID) {
+			return true
+		}
+	}
+
+	if name, ok := details["name"]; ok && clusterName != "" {
+		return strings.Split(fmt.Sprint(name), ".")[0] == clusterName
+	}
+
+	return false
+}
+
+// knownValue returns the given value, or an empty string if it is unknown.
+func knownValue(value string) string {
+	if value == "N/A" || value == "<nil>" {
+		return ""
+	}
+
+	return value
+}
+
+// naValue returns the given value, or N/A if it is empty.
+func naValue(value string) string {
+	if value == "" {
+		return "N/A"
+	}
+
+	return value
+}
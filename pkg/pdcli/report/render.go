@@ -0,0 +1,151 @@
+package pdcli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+const (
+	// Report formats
+	TableFormat = "table"
+	CSVFormat   = "csv"
+	JSONFormat  = "json"
+)
+
+// Render writes the report in the given format.
+// The tables only list the top entries of the counts, the other formats list them all.
+func Render(w io.Writer, r *Report, format string, top int) error {
+	switch format {
+	case TableFormat:
+		return Table(w, r, top)
+	case CSVFormat:
+		return CSV(w, r)
+	case JSONFormat:
+		data, err := json.MarshalIndent(r, "", "  ")
+
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(data))
+
+		return err
+	}
+
+	return fmt.Errorf("invalid format '%s', please use one of: %s, %s, %s", format, TableFormat, CSVFormat, JSONFormat)
+}
+
+// Table writes the report as tables.
+func Table(w io.Writer, r *Report, top int) error {
+	fmt.Fprintf(w, "Incidents from %s to %s: %d\n\n", utils.FormatTime(r.Since), utils.FormatTime(r.Until), r.Incidents)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "METRIC\tSAMPLES\tP50\tP90\tP95\tP99")
+	writePercentiles(tw, "MTTA", r.MTTA)
+	writePercentiles(tw, "MTTR", r.MTTR)
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		header string
+		counts []Count
+		limit  int
+	}{
+		{"ALERT", r.Alerts, top},
+		{"CLUSTER", r.Clusters, top},
+		{"ACKNOWLEDGED BY", r.AckBy, top},
+		{"HOUR", r.Hours, len(r.Hours)},
+	}
+
+	for _, section := range sections {
+		fmt.Fprintln(w)
+		fmt.Fprintf(tw, "%s\tINCIDENTS\n", section.header)
+
+		for i, count := range section.counts {
+			if section.limit > 0 && i == section.limit {
+				break
+			}
+
+			fmt.Fprintf(tw, "%s\t%d\n", count.Key, count.Count)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CSV writes the report as CSV rows of section, key and value.
+// The durations are in seconds.
+func CSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"section", "key", "value"},
+		{"incidents", "total", strconv.Itoa(r.Incidents)},
+	}
+
+	rows = append(rows, percentileRows("mtta", r.MTTA)...)
+	rows = append(rows, percentileRows("mttr", r.MTTR)...)
+	rows = append(rows, countRows("alert", r.Alerts)...)
+	rows = append(rows, countRows("cluster", r.Clusters)...)
+	rows = append(rows, countRows("acknowledged_by", r.AckBy)...)
+	rows = append(rows, countRows("hour", r.Hours)...)
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// writePercentiles writes a table row of the percentiles, N/A is shown when there are no samples.
+func writePercentiles(w io.Writer, metric string, p Percentiles) {
+	if p.Samples == 0 {
+		fmt.Fprintf(w, "%s\t0\tN/A\tN/A\tN/A\tN/A\n", metric)
+		return
+	}
+
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", metric, p.Samples, formatDuration(p.P50), formatDuration(p.P90), formatDuration(p.P95), formatDuration(p.P99))
+}
+
+func percentileRows(section string, p Percentiles) [][]string {
+	return [][]string{
+		{section, "samples", strconv.Itoa(p.Samples)},
+		{section, "p50", seconds(p.P50)},
+		{section, "p90", seconds(p.P90)},
+		{section, "p95", seconds(p.P95)},
+		{section, "p99", seconds(p.P99)},
+	}
+}
+
+func countRows(section string, counts []Count) [][]string {
+	var rows [][]string
+
+	for _, count := range counts {
+		rows = append(rows, []string{section, count.Key, strconv.Itoa(count.Count)})
+	}
+
+	return rows
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 0, 64)
+}
+
+// formatDuration formats a duration to the second, e.g. 1h2m3s.
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
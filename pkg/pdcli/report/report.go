@@ -0,0 +1,295 @@
+package pdcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	alerts "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+const (
+	// Log entry types of the incident acknowledgements and resolutions
+	acknowledgeLogEntry = "acknowledge_log_entry"
+	resolveLogEntry     = "resolve_log_entry"
+)
+
+// Incident is an incident of the report with its time to acknowledge and resolve.
+type Incident struct {
+	ID             string
+	CreatedAt      time.Time
+	Alerts         []string
+	Clusters       []string
+	AcknowledgedBy string
+	TimeToAck      time.Duration
+	TimeToResolve  time.Duration
+	Acknowledged   bool
+	Resolved       bool
+}
+
+// Percentiles are the percentiles of durations.
+type Percentiles struct {
+	Samples int
+	P50     time.Duration
+	P90     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+}
+
+// Count is the number of incidents for a key, e.g. an alert name.
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Report is the incident analytics of a date range.
+type Report struct {
+	Since     time.Time   `json:"since"`
+	Until     time.Time   `json:"until"`
+	Incidents int         `json:"incidents"`
+	MTTA      Percentiles `json:"mtta"`
+	MTTR      Percentiles `json:"mttr"`
+	Alerts    []Count     `json:"alerts"`
+	Clusters  []Count     `json:"clusters"`
+	Hours     []Count     `json:"hours"`
+	AckBy     []Count     `json:"acknowledged_by"`
+}
+
+// MarshalJSON encodes the percentiles in seconds.
+func (p Percentiles) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Samples int     `json:"samples"`
+		P50     float64 `json:"p50_seconds"`
+		P90     float64 `json:"p90_seconds"`
+		P95     float64 `json:"p95_seconds"`
+		P99     float64 `json:"p99_seconds"`
+	}{p.Samples, p.P50.Seconds(), p.P90.Seconds(), p.P95.Seconds(), p.P99.Seconds()})
+}
+
+// GenerateReport fetches the incidents of the teams created between the given times and computes their analytics.
+func GenerateReport(c client.PagerDutyClient, cfg *config.Config, user *pdApi.User, since time.Time, until time.Time) (*Report, error) {
+	opts, _, err := alerts.AssignmentOptions("team", user, cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	incidents, err := ListIncidents(c, opts, since, until)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed []Incident
+
+	for _, incident := range incidents {
+		p, err := ParseIncident(c, incident)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, p)
+	}
+
+	return Compute(parsed, since, until), nil
+}
+
+// ListIncidents fetches the incidents created between the given times, whatever their status.
+// The incidents hidden by the suppression policy of the teams are not included.
+func ListIncidents(c client.PagerDutyClient, opts pdApi.ListIncidentsOptions, since time.Time, until time.Time) ([]pdApi.Incident, error) {
+	opts.Since = since.Format(time.RFC3339)
+	opts.Until = until.Format(time.RFC3339)
+	opts.Statuses = []string{constants.StatusTriggered, constants.StatusAcknowledged, constants.StatusResolved}
+
+	return alerts.GetIncidents(c, &opts)
+}
+
+// ParseIncident fetches the alerts and log entries of the incident, and returns its alert names, clusters and times to acknowledge and resolve.
+// The alert names and clusters are parsed with alerts.ParseIncidentAlerts.
+func ParseIncident(c client.PagerDutyClient, incident pdApi.Incident) (Incident, error) {
+	parsed := Incident{ID: incident.Id}
+
+	createdAt, err := utils.ParseTimestamp(incident.CreatedAt)
+
+	if err != nil {
+		return parsed, err
+	}
+
+	parsed.CreatedAt = createdAt
+
+	incidentAlerts, err := alerts.ParseIncidentAlerts(c, incident)
+
+	if err != nil {
+		return parsed, err
+	}
+
+	for _, alert := range incidentAlerts {
+		parsed.Alerts = append(parsed.Alerts, alert.Name)
+
+		if alert.ClusterName != "" && alert.ClusterName != "N/A" {
+			parsed.Clusters = append(parsed.Clusters, alert.ClusterName)
+		}
+	}
+
+	opts := pdApi.ListIncidentLogEntriesOptions{}
+	opts.Limit = 100
+
+	for {
+		entries, err := c.ListIncidentLogEntries(incident.Id, opts)
+
+		if err != nil {
+			return parsed, err
+		}
+
+		for _, entry := range entries.LogEntries {
+			at, err := utils.ParseTimestamp(entry.CreatedAt)
+
+			if err != nil {
+				return parsed, err
+			}
+
+			switch entry.Type {
+			case acknowledgeLogEntry:
+				if !parsed.Acknowledged || at.Sub(createdAt) < parsed.TimeToAck {
+					parsed.Acknowledged = true
+					parsed.TimeToAck = at.Sub(createdAt)
+					parsed.AcknowledgedBy = entry.Agent.Summary
+				}
+			case resolveLogEntry:
+				parsed.Resolved = true
+				parsed.TimeToResolve = at.Sub(createdAt)
+			}
+		}
+
+		if !entries.More {
+			return parsed, nil
+		}
+
+		opts.Offset += opts.Limit
+	}
+}
+
+// Compute returns the analytics of the given incidents.
+// The hours of the day are in the display timezone.
+func Compute(incidents []Incident, since time.Time, until time.Time) *Report {
+	var tta, ttr []time.Duration
+
+	alertCounts := make(map[string]int)
+	clusterCounts := make(map[string]int)
+	ackCounts := make(map[string]int)
+	hourCounts := make([]int, 24)
+
+	for _, incident := range incidents {
+		if incident.Acknowledged {
+			tta = append(tta, incident.TimeToAck)
+
+			if incident.AcknowledgedBy != "" {
+				ackCounts[incident.AcknowledgedBy]++
+			}
+		}
+
+		if incident.Resolved {
+			ttr = append(ttr, incident.TimeToResolve)
+		}
+
+		// Incidents are counted once per alert name and cluster
+		for _, name := range unique(incident.Alerts) {
+			alertCounts[name]++
+		}
+
+		for _, cluster := range unique(incident.Clusters) {
+			clusterCounts[cluster]++
+		}
+
+		hourCounts[incident.CreatedAt.In(utils.DisplayLocation).Hour()]++
+	}
+
+	report := &Report{
+		Since:     since,
+		Until:     until,
+		Incidents: len(incidents),
+		MTTA:      ComputePercentiles(tta),
+		MTTR:      ComputePercentiles(ttr),
+		Alerts:    sortedCounts(alertCounts),
+		Clusters:  sortedCounts(clusterCounts),
+		AckBy:     sortedCounts(ackCounts),
+	}
+
+	for hour, count := range hourCounts {
+		report.Hours = append(report.Hours, Count{Key: fmt.Sprintf("%02d", hour), Count: count})
+	}
+
+	return report
+}
+
+// ComputePercentiles returns the nearest-rank percentiles of the given durations.
+func ComputePercentiles(durations []time.Duration) Percentiles {
+	p := Percentiles{Samples: len(durations)}
+
+	if len(durations) == 0 {
+		return p
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(percentile float64) time.Duration {
+		index := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+
+		if index < 0 {
+			index = 0
+		}
+
+		return sorted[index]
+	}
+
+	p.P50 = rank(50)
+	p.P90 = rank(90)
+	p.P95 = rank(95)
+	p.P99 = rank(99)
+
+	return p
+}
+
+// sortedCounts returns the counts sorted by decreasing count, then by key.
+func sortedCounts(counts map[string]int) []Count {
+	sorted := []Count{}
+
+	for key, count := range counts {
+		sorted = append(sorted, Count{Key: key, Count: count})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	return sorted
+}
+
+// unique returns the distinct values of the given slice, in order.
+func unique(values []string) []string {
+	var distinct []string
+
+	seen := make(map[string]bool)
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			distinct = append(distinct, value)
+		}
+	}
+
+	return distinct
+}
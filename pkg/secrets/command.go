@@ -0,0 +1,31 @@
+package secrets
+
+import (
+	"fmt"
+)
+
+// command reads the secrets printed by an external command, e.g. 'pass show kite/api-key', like a credential process.
+// The name of the secrets is the command, it is run by the shell.
+type command struct{}
+
+func (command) Get(name string) (string, error) {
+	secret, err := run(nil, "sh", "-c", name)
+
+	if err != nil {
+		return "", err
+	}
+
+	if secret == "" {
+		return "", fmt.Errorf("the command '%s' printed no secret", name)
+	}
+
+	return secret, nil
+}
+
+func (command) Set(name string, value string) error {
+	return fmt.Errorf("the command backend is read-only, please store the secret with your secret manager and reference the command printing it")
+}
+
+func (command) Delete(name string) error {
+	return nil
+}
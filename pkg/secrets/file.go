@@ -0,0 +1,204 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// file stores the secrets in a file encrypted with AES-256-GCM, the key is derived from the passphrase with scrypt.
+type file struct {
+	path       string
+	passphrase func() (string, error)
+
+	// The passphrase is only asked once
+	key     string
+	hasKey  bool
+	secrets map[string]string
+}
+
+// encryptedFile is the content of the encrypted secrets file.
+type encryptedFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+func (f *file) Get(name string) (string, error) {
+	secrets, err := f.load()
+
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := secrets[name]
+
+	if !ok {
+		return "", fmt.Errorf("secret '%s' not found in %s", name, f.path)
+	}
+
+	return secret, nil
+}
+
+func (f *file) Set(name string, value string) error {
+	secrets, err := f.load()
+
+	if err != nil {
+		return err
+	}
+
+	secrets[name] = value
+
+	return f.save(secrets)
+}
+
+func (f *file) Delete(name string) error {
+	secrets, err := f.load()
+
+	if err != nil {
+		return err
+	}
+
+	delete(secrets, name)
+
+	return f.save(secrets)
+}
+
+// load decrypts the secrets file, there are no secrets if it doesn't exist.
+func (f *file) load() (map[string]string, error) {
+	if f.secrets != nil {
+		return f.secrets, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		f.secrets = map[string]string{}
+		return f.secrets, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var encrypted encryptedFile
+
+	err = json.Unmarshal(data, &encrypted)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the secrets file %s", f.path)
+	}
+
+	gcm, err := f.cipher(encrypted.Salt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, encrypted.Nonce, encrypted.Data, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the secrets file %s, please check the passphrase", f.path)
+	}
+
+	secrets := map[string]string{}
+
+	err = json.Unmarshal(plaintext, &secrets)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the secrets file %s", f.path)
+	}
+
+	f.secrets = secrets
+
+	return secrets, nil
+}
+
+// save encrypts the secrets with a new salt and nonce, and writes them to the secrets file.
+func (f *file) save(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+
+	if err != nil {
+		return err
+	}
+
+	encrypted := encryptedFile{Salt: make([]byte, 16)}
+
+	if _, err = rand.Read(encrypted.Salt); err != nil {
+		return err
+	}
+
+	gcm, err := f.cipher(encrypted.Salt)
+
+	if err != nil {
+		return err
+	}
+
+	encrypted.Nonce = make([]byte, gcm.NonceSize())
+
+	if _, err = rand.Read(encrypted.Nonce); err != nil {
+		return err
+	}
+
+	encrypted.Data = gcm.Seal(nil, encrypted.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(encrypted, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(f.path), os.FileMode(0755))
+
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(f.path, data, 0600)
+
+	if err != nil {
+		return fmt.Errorf("cannot save the secrets file '%s': %v", f.path, err)
+	}
+
+	f.secrets = secrets
+
+	return nil
+}
+
+// cipher returns the AES-256-GCM cipher of the passphrase and the given salt.
+func (f *file) cipher(salt []byte) (cipher.AEAD, error) {
+	if !f.hasKey {
+		passphrase, err := f.passphrase()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if passphrase == "" {
+			return nil, fmt.Errorf("the secrets file passphrase can't be empty")
+		}
+
+		f.key, f.hasKey = passphrase, true
+	}
+
+	key, err := scrypt.Key([]byte(f.key), salt, 1<<15, 8, 1, 32)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyring stores the secrets in the system keyring,
+// the Secret Service through secret-tool on Linux and the login keychain through security on macOS.
+type keyring struct{}
+
+func (keyring) Get(name string) (string, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return run(nil, "secret-tool", "lookup", "service", service, "account", name)
+	case "darwin":
+		return run(nil, "security", "find-generic-password", "-s", service, "-a", name, "-w")
+	}
+
+	return "", unsupportedKeyring()
+}
+
+func (keyring) Set(name string, value string) error {
+	var err error
+
+	switch runtime.GOOS {
+	case "linux":
+		// The secret is read from the standard input, it is not exposed in the process arguments
+		_, err = run(strings.NewReader(value), "secret-tool", "store", "--label", service+" "+name, "service", service, "account", name)
+	case "darwin":
+		// With -w last and no value, security prompts for the secret and its confirmation on the standard input,
+		// so it is not exposed in the process arguments either
+		_, err = run(strings.NewReader(value+"\n"+value+"\n"), "security", "add-generic-password", "-U", "-s", service, "-a", name, "-w")
+	default:
+		err = unsupportedKeyring()
+	}
+
+	return err
+}
+
+func (keyring) Delete(name string) error {
+	var err error
+
+	switch runtime.GOOS {
+	case "linux":
+		_, err = run(nil, "secret-tool", "clear", "service", service, "account", name)
+	case "darwin":
+		_, err = run(nil, "security", "delete-generic-password", "-s", service, "-a", name)
+	default:
+		err = unsupportedKeyring()
+	}
+
+	return err
+}
+
+func unsupportedKeyring() error {
+	return fmt.Errorf("the keyring backend is not supported on %s, please use the file or command backend", runtime.GOOS)
+}
+
+// run runs the command and returns its trimmed output.
+func run(stdin *strings.Reader, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	err := cmd.Run()
+
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%s: %v: %s", name, err, message)
+		}
+
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+
+	secret := strings.TrimSpace(stdout.String())
+
+	return secret, nil
+}
@@ -0,0 +1,142 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// Secret backends
+	KeyringBackend   = "keyring"
+	FileBackend      = "file"
+	CommandBackend   = "command"
+	PlaintextBackend = "plaintext"
+
+	// PassphraseEnv is the environment variable of the encrypted file passphrase
+	PassphraseEnv = "KITE_SECRETS_PASSPHRASE"
+
+	// service is the keyring service the secrets are stored under
+	service = "kite"
+)
+
+// Backend stores the secrets by name.
+type Backend interface {
+	Get(name string) (string, error)
+	Set(name string, value string) error
+	Delete(name string) error
+}
+
+// Options configures the secret backends.
+type Options struct {
+	// File is the path of the encrypted secrets file
+	File string
+
+	// Passphrase returns the passphrase of the encrypted secrets file
+	Passphrase func() (string, error)
+}
+
+// Backends returns the names of the backends the secrets can be stored in.
+func Backends() []string {
+	return []string{KeyringBackend, FileBackend, CommandBackend}
+}
+
+// NewBackend returns the secret backend with the given name.
+func NewBackend(name string, opts Options) (Backend, error) {
+	switch name {
+	case KeyringBackend:
+		return keyring{}, nil
+	case FileBackend:
+		if opts.Passphrase == nil {
+			opts.Passphrase = PromptPassphrase
+		}
+
+		return &file{path: opts.File, passphrase: opts.Passphrase}, nil
+	case CommandBackend:
+		return command{}, nil
+	}
+
+	return nil, fmt.Errorf("invalid secret backend '%s', please use one of: %s", name, strings.Join(Backends(), ", "))
+}
+
+// Ref returns the reference of a secret, i.e. '<backend>:<name>'.
+// The name of the command backend secrets is the command printing the secret.
+func Ref(backend string, name string) string {
+	return backend + ":" + name
+}
+
+// ParseRef returns the backend and the name of the secret reference.
+func ParseRef(ref string) (string, string, error) {
+	backend, name, found := strings.Cut(ref, ":")
+
+	if !found || name == "" {
+		return "", "", fmt.Errorf("invalid secret reference '%s', please use '<backend>:<name>'", ref)
+	}
+
+	return backend, name, nil
+}
+
+// Resolve returns the secret of the given reference.
+func Resolve(ref string, opts Options) (string, error) {
+	backendName, name, err := ParseRef(ref)
+
+	if err != nil {
+		return "", err
+	}
+
+	backend, err := NewBackend(backendName, opts)
+
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := backend.Get(name)
+
+	if err != nil {
+		return "", fmt.Errorf("cannot read secret '%s': %v", ref, err)
+	}
+
+	return secret, nil
+}
+
+// Store stores the secret in the given backend and returns its reference.
+func Store(backendName string, name string, secret string, opts Options) (string, error) {
+	backend, err := NewBackend(backendName, opts)
+
+	if err != nil {
+		return "", err
+	}
+
+	err = backend.Set(name, secret)
+
+	if err != nil {
+		return "", fmt.Errorf("cannot store secret '%s' in the %s backend: %v", name, backendName, err)
+	}
+
+	return Ref(backendName, name), nil
+}
+
+// PromptPassphrase returns the passphrase of the environment, or prompts for it when the standard input is a terminal.
+func PromptPassphrase() (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("the secrets file passphrase is required, please set %s", PassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, "Secrets file passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(passphrase), nil
+}
@@ -0,0 +1,164 @@
+package sop
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"golang.org/x/oauth2"
+)
+
+// syncedFile marks the time the documents of the SOP repository were downloaded
+const syncedFile = ".synced"
+
+// IndexCacheDir returns the directory the markdown documents of the SOP repository are downloaded to.
+func IndexCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "kite", "sop-index", constants.SOPRepoOwner, constants.SOPRepo), nil
+}
+
+// DownloadedAt returns when the markdown documents were downloaded to the directory, zero if they never were.
+func DownloadedAt(dir string) time.Time {
+	info, err := os.Stat(filepath.Join(dir, syncedFile))
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// Download downloads the markdown documents of the SOP repository to the directory, from the archive of its default branch.
+// The documents previously downloaded are replaced.
+func Download(token string, dir string) error {
+	ctx := context.Background()
+	client := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+
+	link, _, err := github.NewClient(client).Repositories.GetArchiveLink(ctx, constants.SOPRepoOwner, constants.SOPRepo, github.Tarball, nil, true)
+
+	if err != nil {
+		return fmt.Errorf("cannot download the %s/%s repository: %v", constants.SOPRepoOwner, constants.SOPRepo, err)
+	}
+
+	resp, err := client.Get(link.String())
+
+	if err != nil {
+		return fmt.Errorf("cannot download the %s/%s repository: %v", constants.SOPRepoOwner, constants.SOPRepo, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot download the %s/%s repository: %s", constants.SOPRepoOwner, constants.SOPRepo, resp.Status)
+	}
+
+	// The documents are extracted next to the directory, which is then replaced
+	err = os.MkdirAll(filepath.Dir(dir), 0700)
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+"-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tmp)
+
+	err = extractMarkdown(resp.Body, tmp)
+
+	if err != nil {
+		return fmt.Errorf("cannot extract the %s/%s repository: %v", constants.SOPRepoOwner, constants.SOPRepo, err)
+	}
+
+	err = os.WriteFile(filepath.Join(tmp, syncedFile), nil, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(dir)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, dir)
+}
+
+// extractMarkdown extracts the markdown documents of a gzipped tar archive of a GitHub repository to the directory.
+// The top directory of the archive, named after the repository and the commit, is stripped.
+func extractMarkdown(archive io.Reader, dir string) error {
+	gz, err := gzip.NewReader(archive)
+
+	if err != nil {
+		return err
+	}
+
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+
+	for {
+		header, err := reader.Next()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, name, ok := strings.Cut(header.Name, "/")
+
+		if !ok || header.Typeflag != tar.TypeReg || !strings.EqualFold(path.Ext(name), ".md") {
+			continue
+		}
+
+		// The files outside of the directory are ignored
+		name = path.Clean(name)
+
+		if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			continue
+		}
+
+		file := filepath.Join(dir, filepath.FromSlash(name))
+
+		err = os.MkdirAll(filepath.Dir(file), 0700)
+
+		if err != nil {
+			return err
+		}
+
+		out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(out, reader)
+		out.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+}
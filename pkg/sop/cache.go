@@ -0,0 +1,136 @@
+package sop
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultRef keys the documents of the default branch, fetched without a ref
+const defaultRef = "HEAD"
+
+// CachedDocument is a document of a GitHub repository cached on disk.
+type CachedDocument struct {
+	Content string `json:"content"`
+
+	// ETag validates the cached document with GitHub, unchanged documents are not downloaded again
+	ETag string `json:"etag,omitempty"`
+
+	// FetchedAt is when the document was last downloaded or validated with GitHub
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Cache stores the documents fetched from GitHub, keyed by owner/repo/ref/path.
+type Cache struct {
+	Dir string
+}
+
+// NewCache returns the cache of the documents in the user cache directory.
+func NewCache() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{Dir: filepath.Join(dir, "kite", "sop-documents")}, nil
+}
+
+// file returns the file of a document in the cache.
+func (c *Cache) file(owner string, repo string, ref string, path string) (string, error) {
+	for _, name := range []string{owner, repo} {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return "", fmt.Errorf("invalid repository '%s/%s'", owner, repo)
+		}
+	}
+
+	if ref == "" {
+		ref = defaultRef
+	}
+
+	if ref == "." || ref == ".." {
+		return "", fmt.Errorf("invalid ref '%s'", ref)
+	}
+
+	// The refs may contain slashes, e.g. release/4.12
+	dir := filepath.Join(c.Dir, owner, repo, url.PathEscape(ref))
+	file := filepath.Join(dir, filepath.FromSlash(path)+".json")
+
+	// The path of the URL must not escape the cache
+	if rel, err := filepath.Rel(dir, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid document path '%s'", path)
+	}
+
+	return file, nil
+}
+
+// Get returns a cached document, an error wrapping os.ErrNotExist if it isn't cached.
+func (c *Cache) Get(owner string, repo string, ref string, path string) (*CachedDocument, error) {
+	file, err := c.file(owner, repo, ref, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var document CachedDocument
+
+	err = json.Unmarshal(data, &document)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the cached document '%s': %v", path, err)
+	}
+
+	return &document, nil
+}
+
+// Put stores a document in the cache, replacing the document cached before.
+func (c *Cache) Put(owner string, repo string, ref string, path string, document *CachedDocument) error {
+	file, err := c.file(owner, repo, ref, path)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(document)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+
+	if err != nil {
+		return err
+	}
+
+	// The document is written next to the file, which is then replaced
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+"-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
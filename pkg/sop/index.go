@@ -0,0 +1,60 @@
+package sop
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// LoadIndex returns the index of the SOP repository, read from its local clone when configured.
+// Otherwise the documents are downloaded from GitHub, they are cached and only downloaded again when older than a day or when refreshed.
+// Offline, only the documents downloaded before are searched.
+func LoadIndex(cfg *config.Config, refresh bool) (*Index, error) {
+	if clone := cfg.SOPPath(); clone != "" {
+		return NewIndex(clone)
+	}
+
+	dir, err := IndexCacheDir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	downloadedAt := DownloadedAt(dir)
+
+	var downloadErr error
+
+	if cfg.Offline() {
+		// The documents downloaded before are searched offline, whatever their age
+		if refresh || downloadedAt.IsZero() {
+			return nil, fmt.Errorf("the SOPs can't be downloaded from GitHub offline, set sop.local_path to search a local clone of the SOP repository")
+		}
+	} else if refresh || time.Since(downloadedAt) > constants.SOPIndexMaxAge*time.Hour {
+		if !cfg.HasGitHub() {
+			return nil, fmt.Errorf("the SOPs can't be searched without a GitHub token or a local clone of the SOP repository, run the 'kite login' command or set sop.local_path")
+		}
+
+		downloadErr = Download(cfg.AccessToken, dir)
+
+		// The documents downloaded before are searched when GitHub can't be reached
+		if downloadErr != nil && (downloadedAt.IsZero() || refresh) {
+			return nil, downloadErr
+		}
+	}
+
+	index, err := NewIndex(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	index.Source = fmt.Sprintf("%s/%s downloaded at %s", constants.SOPRepoOwner, constants.SOPRepo, DownloadedAt(dir).Format(time.RFC3339))
+
+	if downloadErr != nil {
+		index.Source += fmt.Sprintf(", it can't be downloaded again: %v", downloadErr)
+	}
+
+	return index, nil
+}
@@ -0,0 +1,56 @@
+package sop
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// ReadLocal returns the content of a document of the SOP repository from its local clone.
+// The document of the checked out branch is read, whatever the ref of its URL.
+func ReadLocal(clone string, owner string, repo string, path string) (string, error) {
+	if !strings.EqualFold(owner, constants.SOPRepoOwner) || !strings.EqualFold(repo, constants.SOPRepo) {
+		return "", fmt.Errorf("the local clone only holds the %s/%s repository", constants.SOPRepoOwner, constants.SOPRepo)
+	}
+
+	file := filepath.Join(clone, filepath.FromSlash(path))
+
+	// The path of the URL must not escape the clone
+	if rel, err := filepath.Rel(clone, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid document path '%s'", path)
+	}
+
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return "", fmt.Errorf("cannot read the document from the local clone: %v", err)
+	}
+
+	return string(data), nil
+}
+
+// Sync pulls the local clone of the SOP repository, its branch is only fast-forwarded.
+func Sync(clone string, stdout io.Writer, stderr io.Writer) error {
+	info, err := os.Stat(filepath.Join(clone, ".git"))
+
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("'%s' is not a git clone of the %s/%s repository", clone, constants.SOPRepoOwner, constants.SOPRepo)
+	}
+
+	cmd := exec.Command("git", "-C", clone, "pull", "--ff-only")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+
+	if err != nil {
+		return fmt.Errorf("cannot pull the SOP repository clone '%s': %v", clone, err)
+	}
+
+	return nil
+}
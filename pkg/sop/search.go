@@ -0,0 +1,296 @@
+package sop
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// Weights of the matches of a search term
+const (
+	titleWeight   = 10
+	headingWeight = 5
+	pathWeight    = 3
+
+	// The matches in the body of a document are counted up to maxBodyMatches per term
+	maxBodyMatches = 5
+)
+
+// Snippets of the search results
+const (
+	maxSnippets      = 3
+	snippetBefore    = 40
+	snippetMaxLength = 120
+)
+
+// Document is a markdown document of the SOP repository.
+type Document struct {
+	// Path is the slash separated path of the document in the repository
+	Path    string
+	Title   string
+	Content string
+}
+
+// URL returns the GitHub URL of the document.
+func (d Document) URL() string {
+	return fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", constants.SOPRepoOwner, constants.SOPRepo, constants.SOPRepoRef, d.Path)
+}
+
+// Result is a document matching a search query.
+type Result struct {
+	Document
+	Score    int
+	Snippets []string
+}
+
+// Index holds the markdown documents of the SOP repository.
+type Index struct {
+	Documents []Document
+
+	// Source describes where the documents were read from
+	Source string
+}
+
+// NewIndex reads the markdown documents of a directory, e.g. the local clone of the SOP repository.
+func NewIndex(dir string) (*Index, error) {
+	index := &Index{Source: dir}
+
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), ".") && file != dir {
+			return filepath.SkipDir
+		}
+
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(file), ".md") {
+			return nil
+		}
+
+		data, err := os.ReadFile(file)
+
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+
+		if err != nil {
+			return err
+		}
+
+		index.Documents = append(index.Documents, newDocument(filepath.ToSlash(rel), string(data)))
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the SOPs of '%s': %v", dir, err)
+	}
+
+	return index, nil
+}
+
+// newDocument returns the document, its title is the first heading or the file name.
+func newDocument(docPath string, content string) Document {
+	title := strings.TrimSuffix(path.Base(docPath), path.Ext(docPath))
+
+	for _, line := range strings.Split(content, "\n") {
+		if heading, ok := headingText(line); ok {
+			title = heading
+			break
+		}
+	}
+
+	return Document{Path: docPath, Title: title, Content: content}
+}
+
+// headingText returns the text of a markdown heading line.
+func headingText(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, "#")
+
+	if trimmed == line || len(line)-len(trimmed) > 6 || !strings.HasPrefix(trimmed, " ") {
+		return "", false
+	}
+
+	return strings.TrimSpace(trimmed), true
+}
+
+// Search returns the documents matching every term of the query, ranked by the matches in their title, headings, path and body.
+// At most limit results are returned, all of them when limit is zero.
+func (i *Index) Search(query string, limit int) []Result {
+	terms := strings.Fields(strings.ToLower(query))
+
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []Result
+
+	for _, doc := range i.Documents {
+		if result, ok := match(doc, terms); ok {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+
+		return results[a].Path < results[b].Path
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// match scores the document, it matches when every term is found in its path or content.
+func match(doc Document, terms []string) (Result, bool) {
+	result := Result{Document: doc}
+
+	title := strings.ToLower(doc.Title)
+	docPath := strings.ToLower(doc.Path)
+	lines := strings.Split(doc.Content, "\n")
+
+	for _, term := range terms {
+		found := false
+
+		if strings.Contains(title, term) {
+			result.Score += titleWeight
+			found = true
+		}
+
+		if strings.Contains(docPath, term) {
+			result.Score += pathWeight
+			found = true
+		}
+
+		bodyMatches := 0
+
+		for _, line := range lines {
+			lower := strings.ToLower(line)
+
+			if !strings.Contains(lower, term) {
+				continue
+			}
+
+			found = true
+
+			if _, ok := headingText(line); ok {
+				result.Score += headingWeight
+			} else if bodyMatches < maxBodyMatches {
+				result.Score++
+				bodyMatches++
+			}
+		}
+
+		if !found {
+			return Result{}, false
+		}
+	}
+
+	result.Snippets = snippets(lines, terms)
+
+	return result, true
+}
+
+// snippets returns the first lines of the body matching a term, cut around the match.
+func snippets(lines []string, terms []string) []string {
+	var snippets []string
+
+	for n, line := range lines {
+		if _, ok := headingText(line); ok {
+			continue
+		}
+
+		line = strings.Join(strings.Fields(line), " ")
+
+		for _, term := range terms {
+			index := matchIndex(line, term)
+
+			if index < 0 {
+				continue
+			}
+
+			snippets = append(snippets, fmt.Sprintf("L%d: %s", n+1, cut(line, index)))
+			break
+		}
+
+		if len(snippets) == maxSnippets {
+			break
+		}
+	}
+
+	return snippets
+}
+
+// matchIndex returns the byte index in the line of the first case-insensitive match of the lowercase term, -1 if none.
+// Lowercasing may change the byte length of a character, e.g. Ⱥ, the index in the lowercase line is mapped back to the line.
+func matchIndex(line string, term string) int {
+	index := strings.Index(strings.ToLower(line), term)
+
+	if index < 0 {
+		return -1
+	}
+
+	offset := 0
+
+	for i, r := range line {
+		if offset >= index {
+			return i
+		}
+
+		offset += utf8.RuneLen(unicode.ToLower(r))
+	}
+
+	return len(line)
+}
+
+// cut returns the part of the line around the match at the given byte index.
+func cut(line string, index int) string {
+	start := 0
+
+	if index > snippetBefore {
+		start = index - snippetBefore
+	}
+
+	end := start + snippetMaxLength
+
+	if end > len(line) {
+		end = len(line)
+	}
+
+	// Do not split the multi-byte characters
+	for start > 0 && !utf8.RuneStart(line[start]) {
+		start--
+	}
+
+	for end < len(line) && !utf8.RuneStart(line[end]) {
+		end++
+	}
+
+	snippet := line[start:end]
+
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+
+	if end < len(line) {
+		snippet += "..."
+	}
+
+	return snippet
+}
@@ -18,6 +18,15 @@ const (
 	OncallTableTitle          = "ONCALL"
 	NextOncallTableTitle      = "[ NEXT ONCALL ]"
 	AllTeamsOncallTableTitle  = "[ ALL TEAMS ONCALL ]"
+	OncallDateTitle           = "[ GO TO DATE ]"
+	OncallOverrideTitle       = "[ CREATE OVERRIDE ]"
+	OncallOverridesTitle      = "[ UPCOMING OVERRIDES ]"
+	EscalationChainTableTitle = "ESCALATION CHAIN"
+	RespondersTitle           = "[ REQUEST RESPONDERS ]"
+	HandoverTitle             = "[ SHIFT HANDOVER ]"
+	ClusterHistoryTitle       = "[ CLUSTER HISTORY ]"
+	ViewsTableTitle           = "[ VIEWS ]"
+	SOPSearchTitle            = "[ SOP SEARCH ]"
 
 	// Page Titles
 	AlertsPageTitle          = "Alerts"
@@ -32,15 +41,28 @@ const (
 	OncallPageTitle          = "Oncall Layer"
 	NextOncallPageTitle      = "Next Oncall"
 	AllTeamsOncallPageTitle  = "All Teams Oncall"
+	OncallDatePageTitle      = "Oncall Date"
+	OncallOverridePageTitle  = "Oncall Override"
+	OncallOverridesPageTitle = "Oncall Overrides"
+	EscalationChainPageTitle = "Escalation Chain"
+	RespondersPageTitle      = "Responders"
+	HandoverPageTitle        = "Handover"
+	ClusterHistoryPageTitle  = "Cluster History"
 	ServiceLogsPageTitle     = "Service Logs"
+	ViewsPageTitle           = "Views"
+	SOPSearchTabTitle        = "SOP search"
 
 	//Footer
 	FooterText                = "[Esc] Go Back"
-	FooterTextAlerts          = "[R] Refresh Alerts | [1] Acknowledged Incidents | [2] Trigerred Incidents\n" + FooterText
-	FooterTextTrigerredAlerts = "[1] Acknowledged Incidents | [2] Trigerred Incidents\n" + FooterText
+	FooterTextAlerts          = "[R] Refresh Alerts | [V] Switch View | [H] Toggle Suppressed | [Z] Toggle Relative Times | [/] Search SOPs\n" + FooterText
+	FooterTextTrigerredAlerts = "[V] Switch View\n" + FooterText
+	FooterTextViews           = "[ENTER] Select View\n" + FooterText
+	FooterTextResponders      = "[ENTER] Request Responder\n" + FooterText
+	FooterTextHandover        = "[CTRL + W] Save Handover | [CTRL + Q] Quit Without Saving"
 	FooterTextAckIncidents    = "[ENTER] View Incident \n " + FooterText
-	FooterTextIncidents       = "[ENTER] Select Incident | [CTRL+A] Acknowledge Incidents | [V] View Incident Alerts\n" + FooterText
-	FooterTextOncall          = "[N] Your Next Oncall Schedule | [A] All Teams Oncall | [<-] Previous Layer Oncall | [->] Next Layer Oncall \n" + FooterText
+	FooterTextIncidents       = "[ENTER] Select Incident | [CTRL+A] Acknowledge Incidents | [V] View Incident Alerts | [P] Request Responders\n" + FooterText
+	FooterTextOncall          = "[N] Your Next Oncall Schedule | [A] All Teams Oncall | [<-] Previous Layer Oncall | [->] Next Layer Oncall | [[] Previous Day | []] Next Day | [G] Go To Date | [O] Create Override | [L] List Overrides | [Z] Toggle Relative Times\n" + FooterText
+	FooterTextOverrides       = "[D] Delete Override\n" + FooterText
 	TerminalFooterText        = "[CTRL + N] Next Slide | [CTRL + P] Previous Slide | [CTRL + S] Add Slide | [CTRL + E] Exit Slide | [CTRL + B] + [Num] Change to Slide with [Num]  | [CTRL + Q] Quit "
 	TerminalFooterEscapeState = "Enter the Slide Number to Switch To : "
 
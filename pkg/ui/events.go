@@ -5,7 +5,6 @@ import (
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/gdamore/tcell/v2"
-	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/ocm"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
@@ -17,17 +16,16 @@ func (tui *TUI) SetAlertsTableEvents(alerts []pdcli.Alert) {
 	tui.Table.SetSelectedFunc(func(row int, column int) {
 		var alertData string
 
-		alertID := tui.Table.GetCell(row, 1).Text
-
-		for _, alert := range alerts {
-			if alertID == alert.AlertID {
-				utils.InfoLogger.Printf("GET: fetching alert metadata for alert ID: %s", alertID)
-				alertData = pdcli.ParseAlertMetaData(alert)
-				tui.ClusterName = alert.ClusterName
-				tui.ClusterID = alert.ClusterID
-				tui.SOPLink = alert.Sop
-				break
-			}
+		// Each row references the alert it displays
+		if alert, ok := tui.Table.GetCell(row, 0).GetReference().(pdcli.Alert); ok {
+			utils.InfoLogger.Printf("GET: fetching alert metadata for alert ID: %s", alert.AlertID)
+			alertData = pdcli.ParseAlertMetaData(alert)
+			tui.ClusterName = alert.ClusterName
+			tui.ClusterID = alert.ClusterID
+			tui.SOPLink = alert.Sop
+			tui.AlertName = alert.Name
+			tui.ServiceID = alert.ServiceID
+			tui.IncidentID = alert.IncidentID
 		}
 
 		tui.AlertMetadata.SetText(alertData)
@@ -35,7 +33,7 @@ func (tui *TUI) SetAlertsTableEvents(alerts []pdcli.Alert) {
 
 		// Do not prompt for cluster login if there's no cluster ID associated with the alert (v3 clusters)
 		if tui.ClusterID != "N/A" && tui.ClusterID != "" && alertData != "" {
-			secondaryWindowText := fmt.Sprintf("Press 'Y' to log into the cluster: %s\nPress 'S' to view the SOP\nPress 'L' to view service logs", tui.ClusterName)
+			secondaryWindowText := tui.alertPrompt(tui.ClusterName)
 			tui.SecondaryWindow.SetText(secondaryWindowText).SetTextColor(PromptTextColor)
 		}
 	})
@@ -47,25 +45,30 @@ func (tui *TUI) SetAckTableEvents() {
 	tui.SelectedIncidents = make(map[string]string)
 	tui.IncidentsTable.SetSelectedFunc(func(row, column int) {
 		var incident pdApi.Incident
-		client, _ := client.NewClient().Connect()
 		incidentID := tui.IncidentsTable.GetCell(row, 0).Text
 		incident.Id = incidentID
+		tui.IncidentID = incidentID
 		var clusterName string
 		var alertData string
 
-		alerts, _ := pdcli.GetIncidentAlerts(client, incident)
+		alerts, _ := pdcli.GetIncidentAlerts(tui.Client, incident)
 		Alert := alerts[0]
 
 		for _, alert := range alerts {
 			if incidentID == alert.IncidentID {
 				alertData = pdcli.ParseAlertMetaData(alert)
 				clusterName = alert.ClusterName
+				tui.ClusterName = alert.ClusterName
 				tui.ClusterID = alert.ClusterID
+				tui.ServiceID = alert.ServiceID
+				tui.SOPLink = alert.Sop
+				tui.AlertName = alert.Name
 				break
 			}
 		}
 		if len(alerts) == 1 {
 			alertData = pdcli.ParseAlertMetaData(Alert)
+			tui.ServiceID = Alert.ServiceID
 			tui.AlertMetadata.SetText(alertData)
 			tui.Pages.AddAndSwitchToPage(AckAlertDataPage, tui.AlertMetadata, true)
 			tui.Footer.SetText(FooterText)
@@ -77,7 +80,7 @@ func (tui *TUI) SetAckTableEvents() {
 		}
 		// Do not prompt for cluster login if there's no cluster ID associated with the alert (v3 clusters)
 		if tui.ClusterID != "N/A" && tui.ClusterID != "" && alertData != "" {
-			secondaryWindowText := fmt.Sprintf("Press 'Y' to log into the cluster: %s\nPress 'S' to view the SOP\nPress 'L' to view service logs", clusterName)
+			secondaryWindowText := tui.alertPrompt(clusterName)
 			tui.SecondaryWindow.SetText(secondaryWindowText)
 		}
 	})
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// InitHandoverUI initializes the handover report preview, the report can be edited before it is saved.
+// Pressing Ctrl+W saves the edited report with the given function and exits the application.
+func (tui *TUI) InitHandoverUI(report string, save func(string) error) {
+	editor := tview.NewTextArea().
+		SetText(report, false)
+
+	editor.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyCtrlW {
+			return event
+		}
+
+		err := save(editor.GetText())
+
+		if err != nil {
+			utils.ErrorLogger.Print(err)
+			return nil
+		}
+
+		tui.App.Stop()
+
+		return nil
+	})
+
+	editor.
+		SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderAttributes(tcell.AttrDim).
+		SetBorderPadding(1, 1, 1, 1).
+		SetTitle(fmt.Sprintf(TitleFmt, HandoverTitle))
+
+	tui.Pages.AddAndSwitchToPage(HandoverPageTitle, editor, true)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	report "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/report"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// InitClusterHistoryUI initializes the cluster history page of the selected alert.
+// It lists the incidents of the teams raised for the same cluster during the history window, including the resolved ones.
+func (tui *TUI) InitClusterHistoryUI() {
+	alert := pdcli.Alert{
+		IncidentID:  tui.IncidentID,
+		ClusterID:   tui.ClusterID,
+		ClusterName: tui.ClusterName,
+		ServiceID:   tui.ServiceID,
+	}
+
+	view := tview.NewTextView().SetText("Fetching the incident history...")
+
+	view.
+		SetScrollable(true).
+		SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderPadding(1, 1, 1, 1).
+		SetBorderAttributes(tcell.AttrDim).
+		SetTitle(fmt.Sprintf(TitleFmt, ClusterHistoryTitle))
+
+	tui.PreviousPage, _ = tui.Pages.GetFrontPage()
+	tui.Pages.AddAndSwitchToPage(ClusterHistoryPageTitle, view, true)
+	tui.Footer.SetText(FooterText)
+
+	// The alerts of the candidate incidents are fetched outside of the event loop, the page is updated once they are
+	go func() {
+		utils.InfoLogger.Printf("GET: fetching incident history of cluster %s", tui.ClusterName)
+		history, err := report.ClusterIncidentHistory(tui.Client, tui.Config, tui.User, alert, time.Now())
+
+		tui.App.QueueUpdateDraw(func() {
+			if err != nil {
+				utils.ErrorLogger.Print(err)
+				view.SetText(fmt.Sprintf("Cannot fetch the incident history: %v", err))
+				return
+			}
+
+			view.SetText(report.ParseClusterHistory(history))
+		})
+	}()
+}
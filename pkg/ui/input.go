@@ -7,11 +7,12 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"time"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/gdamore/tcell/v2"
 
-	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
@@ -49,6 +50,8 @@ func (tui *TUI) initKeyboard() {
 					tui.Pages.SwitchToPage(IncidentsPageTitle)
 				case AckAlertDataPage:
 					tui.Pages.SwitchToPage(AckIncidentsPageTitle)
+				case EscalationChainPageTitle, RespondersPageTitle, ClusterHistoryPageTitle:
+					tui.Pages.SwitchToPage(tui.PreviousPage)
 				default:
 					tui.InitAlertsUI(tui.Alerts, AlertsTableTitle, AlertsPageTitle)
 					tui.Pages.SwitchToPage(AlertsPageTitle)
@@ -57,7 +60,8 @@ func (tui *TUI) initKeyboard() {
 			}
 			// Check if oncall command is executed
 			if title, _ := tui.Pages.GetFrontPage(); strings.Contains(title, "Oncall") {
-				tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, 2))
+				tui.CurrentOnCallPage = tui.DefaultOnCallPage
+				tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
 				tui.Footer.SetText(FooterTextOncall)
 			}
 			return nil
@@ -168,26 +172,25 @@ func (tui *TUI) setupAlertsPageInput() {
 
 		tui.Pages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 
-			if event.Rune() == '1' {
-				utils.InfoLogger.Print("Switching to acknowledged incidents view")
-				tui.SeedAckIncidentsUI()
-
-				if len(tui.Incidents) == 0 {
-					utils.InfoLogger.Printf("No acknowledged incidents assigned found")
-				}
-
-				tui.Pages.SwitchToPage(AckIncidentsPageTitle)
+			// The input capture remains set when switching to a page without one
+			if title, _ := tui.Pages.GetFrontPage(); title != AlertsPageTitle {
+				return event
 			}
 
-			if event.Rune() == '2' {
-				utils.InfoLogger.Print("Switching to incidents view")
-				tui.SeedIncidentsUI()
+			if event = tui.bindKey(event, config.AlertsPageActions); event == nil {
+				return nil
+			}
 
-				if len(tui.Incidents) == 0 {
-					utils.InfoLogger.Printf("No trigerred incidents assigned to found")
-				}
+			if event.Rune() == 'v' || event.Rune() == 'V' {
+				utils.InfoLogger.Print("Switching view")
+				tui.InitViewsUI()
+				return nil
+			}
 
-				tui.Pages.SwitchToPage(IncidentsPageTitle)
+			if event.Rune() == '/' {
+				utils.InfoLogger.Print("Opening the SOP search in a new tab")
+				ViewSOPSearch(tui, "")
+				return nil
 			}
 
 			// Alerts refresh
@@ -195,6 +198,23 @@ func (tui *TUI) setupAlertsPageInput() {
 				utils.InfoLogger.Print("Refreshing alerts...")
 				tui.SeedAlertsUI()
 			}
+
+			// Toggle the relative times
+			if event.Rune() == 'z' || event.Rune() == 'Z' {
+				utils.RelativeTimes = !utils.RelativeTimes
+				utils.InfoLogger.Printf("Displaying relative times: %t", utils.RelativeTimes)
+				tui.InitAlertsUI(tui.Alerts, AlertsTableTitle, AlertsPageTitle)
+				return nil
+			}
+
+			// Toggle the suppressed incidents
+			if event.Rune() == 'h' || event.Rune() == 'H' {
+				tui.ShowSuppressed = !tui.ShowSuppressed
+				utils.InfoLogger.Printf("Showing suppressed incidents: %t", tui.ShowSuppressed)
+				tui.SeedAlertsUI()
+				tui.InitAlertsSecondaryView()
+				return nil
+			}
 			return event
 		})
 	}
@@ -203,6 +223,22 @@ func (tui *TUI) setupAlertsPageInput() {
 func (tui *TUI) setupIncidentsPageInput() {
 	if title, _ := tui.Pages.GetFrontPage(); title == IncidentsPageTitle {
 		tui.Pages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+
+			// The input capture remains set when switching to a page without one
+			if title, _ := tui.Pages.GetFrontPage(); title != IncidentsPageTitle {
+				return event
+			}
+
+			if event = tui.bindKey(event, config.IncidentsPageActions); event == nil {
+				return nil
+			}
+
+			if event.Rune() == 'P' || event.Rune() == 'p' {
+				row, _ := tui.IncidentsTable.GetSelection()
+				tui.InitRespondersUI(tui.IncidentsTable.GetCell(row, 0).Text)
+				return nil
+			}
+
 			if event.Key() == tcell.KeyCtrlA {
 				for _, v := range tui.SelectedIncidents {
 					if v != "" {
@@ -219,25 +255,30 @@ func (tui *TUI) setupIncidentsPageInput() {
 			if event.Rune() == 'V' || event.Rune() == 'v' {
 				row, _ := tui.IncidentsTable.GetSelection()
 				var incident pdApi.Incident
-				client, _ := client.NewClient().Connect()
 				incidentID := tui.IncidentsTable.GetCell(row, 0).Text
 				incident.Id = incidentID
+				tui.IncidentID = incidentID
 				var clusterName string
 				var alertData string
 
-				alerts, _ := pdcli.GetIncidentAlerts(client, incident)
+				alerts, _ := pdcli.GetIncidentAlerts(tui.Client, incident)
 				Alert := alerts[0]
 
 				for _, alert := range alerts {
 					if incidentID == alert.IncidentID {
 						alertData = pdcli.ParseAlertMetaData(alert)
 						clusterName = alert.ClusterName
+						tui.ClusterName = alert.ClusterName
 						tui.ClusterID = alert.ClusterID
+						tui.ServiceID = alert.ServiceID
+						tui.SOPLink = alert.Sop
+						tui.AlertName = alert.Name
 						break
 					}
 				}
 				if len(alerts) == 1 {
 					alertData = pdcli.ParseAlertMetaData(Alert)
+					tui.ServiceID = Alert.ServiceID
 					tui.AlertMetadata.SetText(alertData)
 					tui.Pages.AddAndSwitchToPage(AlertMetadata, tui.AlertMetadata, true)
 
@@ -248,7 +289,7 @@ func (tui *TUI) setupIncidentsPageInput() {
 				}
 				// Do not prompt for cluster login if there's no cluster ID associated with the alert (v3 clusters)
 				if tui.ClusterID != "N/A" && tui.ClusterID != "" && alertData != "" {
-					secondaryWindowText := fmt.Sprintf("Press 'Y' to log into the cluster: %s\nPress 'S' to view the SOP\nPress 'L' to view service logs", clusterName)
+					secondaryWindowText := tui.alertPrompt(clusterName)
 					tui.SecondaryWindow.SetText(secondaryWindowText)
 				}
 			}
@@ -260,6 +301,10 @@ func (tui *TUI) setupIncidentsPageInput() {
 func (tui *TUI) setupAlertDetailsPageInput() {
 	tui.AlertMetadata.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 
+		if event = tui.bindKey(event, config.AlertDetailsPageActions); event == nil {
+			return nil
+		}
+
 		if event.Rune() == 'Y' || event.Rune() == 'y' {
 			// Get ocm-conatiner executable from PATH
 			ocmContainer, err := exec.LookPath("ocm-container")
@@ -280,15 +325,54 @@ func (tui *TUI) setupAlertDetailsPageInput() {
 			tui.fetchClusterServiceLogs()
 		}
 
+		if event.Rune() == 'P' || event.Rune() == 'p' {
+			if tui.IncidentID == "" {
+				utils.InfoLogger.Print("No incident associated with the alert")
+				return nil
+			}
+			tui.InitRespondersUI(tui.IncidentID)
+			return nil
+		}
+
+		if event.Rune() == 'E' || event.Rune() == 'e' {
+			if tui.ServiceID == "" {
+				utils.InfoLogger.Print("No service associated with the alert")
+				return nil
+			}
+			utils.InfoLogger.Printf("GET: fetching on-call chain of service %s", tui.ServiceID)
+			tui.InitEscalationChainUI(tui.ServiceID)
+			return nil
+		}
+
+		if event.Rune() == 'C' || event.Rune() == 'c' {
+			if tui.ClusterID == "N/A" && tui.ClusterName == "N/A" {
+				utils.InfoLogger.Print("No cluster associated with the alert")
+				return nil
+			}
+			tui.InitClusterHistoryUI()
+			return nil
+		}
+
 		if event.Rune() == 'S' || event.Rune() == 's' {
 			if tui.SOPLink == "" || tui.SOPLink == "<nil>" {
 				utils.InfoLogger.Print("No SOP mentioned for the alert")
 				return nil
 			}
+			if !tui.Config.HasSOPs() {
+				utils.InfoLogger.Printf("No GitHub token or local SOP clone configured, open the SOP in a browser: %s", tui.SOPLink)
+				return nil
+			}
 			utils.InfoLogger.Print("Opening SOP in a new tab")
 			ViewAlertSOP(tui, tui.SOPLink)
 		}
 
+		// Search the SOPs matching the alert, e.g. when it has no SOP or a stale one
+		if event.Rune() == '/' {
+			utils.InfoLogger.Print("Opening the SOP search in a new tab")
+			ViewSOPSearch(tui, tui.AlertName)
+			return nil
+		}
+
 		return event
 	})
 }
@@ -297,13 +381,67 @@ func (tui *TUI) setupOncallPageInput() {
 	if title, _ := tui.Pages.GetFrontPage(); strings.Contains(title, OncallPageTitle) {
 		tui.Pages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 
+			// Do not handle the keys typed in the date navigator and the override form
+			if title, _ := tui.Pages.GetFrontPage(); title == OncallDatePageTitle || title == OncallOverridePageTitle {
+				return event
+			}
+
+			// The overrides page only handles the deletion of the selected override
+			if title, _ := tui.Pages.GetFrontPage(); title == OncallOverridesPageTitle {
+				if event = tui.bindKey(event, config.OverridesPageActions); event == nil {
+					return nil
+				}
+
+				if event.Rune() == 'd' || event.Rune() == 'D' {
+					tui.DeleteSelectedOverride()
+					return nil
+				}
+
+				return event
+			}
+
+			if event = tui.bindKey(event, config.OncallPageActions); event == nil {
+				return nil
+			}
+
+			// Date navigator
+			if tui.Client != nil {
+				switch event.Rune() {
+				case '[':
+					utils.InfoLogger.Print("Viewing previous day on-call")
+					tui.ShiftOncallWindow(-time.Hour * 24)
+					return nil
+				case ']':
+					utils.InfoLogger.Print("Viewing next day on-call")
+					tui.ShiftOncallWindow(time.Hour * 24)
+					return nil
+				case 'g', 'G':
+					tui.InitOncallDateUI()
+					return nil
+				case 'o', 'O':
+					tui.InitOverrideFormUI()
+					return nil
+				case 'l', 'L':
+					tui.InitOverridesUI()
+					return nil
+				}
+			}
+
+			// Toggle the relative times
+			if event.Rune() == 'z' || event.Rune() == 'Z' {
+				utils.RelativeTimes = !utils.RelativeTimes
+				utils.InfoLogger.Printf("Displaying relative times: %t", utils.RelativeTimes)
+				tui.RefreshOncallUI()
+				return nil
+			}
+
 			if tui.NextOncallTable != nil {
 				if event.Rune() == 'N' || event.Rune() == 'n' {
 					utils.InfoLogger.Print("Viewing user next on-call schedule")
 					tui.Pages.SwitchToPage(NextOncallPageTitle)
 
 					if len(tui.AckIncidents) == 0 {
-						utils.InfoLogger.Print("You are not scheduled for any oncall duties for the next 3 months. Cheer up!")
+						utils.InfoLogger.Print("You are not scheduled for any oncall duties for the next 90 days. Cheer up!")
 					}
 				}
 			}
@@ -321,7 +459,7 @@ func (tui *TUI) setupOncallPageInput() {
 				tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
 			}
 			if event.Key() == tcell.KeyRight {
-				if tui.CurrentOnCallPage < 4 {
+				if tui.CurrentOnCallPage < tui.OnCallLayerCount-1 {
 					tui.CurrentOnCallPage += 1
 				}
 				tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
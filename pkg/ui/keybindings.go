@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+// bindKey translates the key of a page action configured in the keybindings to its default key, which the page handles.
+// The default key of an action bound to another key is ignored.
+func (tui *TUI) bindKey(event *tcell.EventKey, actions []string) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune || tui.Config == nil || len(tui.Config.Keybindings) == 0 {
+		return event
+	}
+
+	key := unicode.ToLower(event.Rune())
+	defaults := config.DefaultKeybindings()
+
+	for _, action := range actions {
+		if keyRune(tui.Config.Keybinding(action)) == key {
+			return tcell.NewEventKey(tcell.KeyRune, keyRune(defaults[action]), event.Modifiers())
+		}
+	}
+
+	for _, action := range actions {
+		if keyRune(defaults[action]) == key {
+			return nil
+		}
+	}
+
+	return event
+}
+
+// keyRune returns the rune of a single character key.
+func keyRune(key string) rune {
+	r, _ := utf8.DecodeRuneInString(key)
+	return unicode.ToLower(r)
+}
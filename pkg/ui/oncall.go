@@ -0,0 +1,389 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/gdamore/tcell/v2"
+	oncall "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// InitOncallUI initializes a TUI table page for each of the given on-call layers.
+// The page of the current layer is displayed.
+func (tui *TUI) InitOncallUI(layers []oncall.OncallLayer, current int) {
+	// Remove the pages of the previously displayed layers
+	for idx := 0; idx < tui.OnCallLayerCount; idx++ {
+		tui.Pages.RemovePage(fmt.Sprintf("%s%d", OncallPageTitle, idx))
+	}
+
+	for idx, layer := range layers {
+		headers, data := getOncallTableData(layer.Users)
+		tui.Table = tui.InitTable(headers, data, false, false, fmt.Sprintf("[ %s %s ]", OncallTableTitle, layer.LayerId))
+		tui.Pages.AddPage(fmt.Sprintf("%s%d", OncallPageTitle, idx), tui.Table, true, false)
+	}
+
+	tui.OncallLayers = layers
+	tui.OnCallLayerCount = len(layers)
+	tui.CurrentOnCallPage = current
+	tui.DefaultOnCallPage = current
+	tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
+}
+
+// SeedOncallUI fetches the on-call layers of the current on-call window and initializes the on-call pages.
+func (tui *TUI) SeedOncallUI() {
+	org := tui.Config.Organization()
+
+	utils.InfoLogger.Printf("GET: fetching on-call data from %s to %s", tui.OncallSince.Format(time.RFC3339), tui.OncallUntil.Format(time.RFC3339))
+	layers, err := oncall.TeamSREOnCall(tui.Client, org, tui.OncallSince, tui.OncallUntil)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+		return
+	}
+
+	current := oncall.CurrentLayerIndex(layers, tui.OncallAt)
+	primary, secondary := OncallRoles(layers[current], org.PrimaryScheduleID, org.SecondaryScheduleID)
+
+	tui.InitOncallUI(layers, current)
+	tui.InitOnCallSecondaryView(tui.Username, primary, secondary)
+	tui.Footer.SetText(FooterTextOncall)
+}
+
+// ShiftOncallWindow moves the on-call window by the given duration and re-fetches the on-call layers.
+func (tui *TUI) ShiftOncallWindow(d time.Duration) {
+	tui.OncallSince = tui.OncallSince.Add(d)
+	tui.OncallUntil = tui.OncallUntil.Add(d)
+	tui.OncallAt = tui.OncallAt.Add(d)
+	tui.SeedOncallUI()
+}
+
+// RefreshOncallUI re-renders the on-call pages, e.g. after the timestamps display is changed.
+// The currently displayed page is kept.
+func (tui *TUI) RefreshOncallUI() {
+	page, _ := tui.Pages.GetFrontPage()
+	current, defaultPage := tui.CurrentOnCallPage, tui.DefaultOnCallPage
+
+	tui.InitOncallUI(tui.OncallLayers, current)
+	tui.DefaultOnCallPage = defaultPage
+
+	if tui.NextOncallTable != nil {
+		tui.InitNextOncallUI(tui.NextOncall)
+	}
+
+	if tui.AllTeamsOncallTable != nil {
+		tui.InitAllTeamsOncallUI(tui.AllTeamsOncall)
+	}
+
+	tui.Pages.SwitchToPage(page)
+}
+
+// InitOncallDateUI initializes the date navigator input.
+// The on-call layers around the entered time are fetched, the time is parsed with utils.ParseTime.
+func (tui *TUI) InitOncallDateUI() {
+	input := tview.NewInputField().
+		SetLabel(fmt.Sprintf("Go to, %s (e.g. saturday 03:00, tomorrow, in 3h, 2021-10-25T03:00:00Z): ", utils.DisplayLocation)).
+		SetFieldWidth(40)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		at, err := utils.ParseTime(input.GetText(), time.Now().In(utils.DisplayLocation))
+
+		if err != nil {
+			utils.ErrorLogger.Print(err)
+			return
+		}
+
+		utils.InfoLogger.Printf("Viewing on-call at %s", at.Format(time.RFC3339))
+		tui.OncallSince, tui.OncallUntil = oncall.AroundWindow(at)
+		tui.OncallAt = at
+		tui.Pages.RemovePage(OncallDatePageTitle)
+		tui.SeedOncallUI()
+	})
+
+	input.
+		SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderAttributes(tcell.AttrDim).
+		SetBorderPadding(1, 1, 1, 1).
+		SetTitle(fmt.Sprintf(TitleFmt, OncallDateTitle))
+
+	tui.Pages.AddAndSwitchToPage(OncallDatePageTitle, input, true)
+	tui.Footer.SetText(FooterText)
+}
+
+// InitOverrideFormUI initializes the form creating an override of an on-call schedule.
+// The time range defaults to the displayed layer, the user defaults to the current user.
+func (tui *TUI) InitOverrideFormUI() {
+	org := tui.Config.Organization()
+	roles, ids := oncall.ScheduleRoles(org)
+
+	if len(roles) == 0 {
+		utils.ErrorLogger.Print("No on-call schedules configured, run the 'kite config init' command")
+		return
+	}
+
+	var start, end string
+
+	if tui.CurrentOnCallPage < len(tui.OncallLayers) {
+		layer := tui.OncallLayers[tui.CurrentOnCallPage]
+		start = layer.Start.In(utils.DisplayLocation).Format("2006-01-02 15:04")
+		end = layer.End.In(utils.DisplayLocation).Format("2006-01-02 15:04")
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Schedule", roles, 0, nil).
+		AddInputField("User (ID or email, empty for yourself)", "", 40, nil, nil).
+		AddInputField(fmt.Sprintf("Start, %s", utils.DisplayLocation), start, 40, nil, nil).
+		AddInputField(fmt.Sprintf("End, %s", utils.DisplayLocation), end, 40, nil, nil)
+
+	form.AddButton("Create", func() {
+		_, role := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		user := form.GetFormItem(1).(*tview.InputField).GetText()
+		now := time.Now().In(utils.DisplayLocation)
+
+		start, err := utils.ParseTime(form.GetFormItem(2).(*tview.InputField).GetText(), now)
+
+		if err != nil {
+			utils.ErrorLogger.Print(err)
+			return
+		}
+
+		end, err := utils.ParseTime(form.GetFormItem(3).(*tview.InputField).GetText(), now)
+
+		if err != nil {
+			utils.ErrorLogger.Print(err)
+			return
+		}
+
+		if user == "" {
+			currentUser, err := tui.Client.GetCurrentUser(pdApi.GetCurrentUserOptions{})
+
+			if err != nil {
+				utils.ErrorLogger.Print(err)
+				return
+			}
+
+			user = currentUser.ID
+		}
+
+		userID, err := oncall.ResolveUser(tui.Client, user)
+
+		if err != nil {
+			utils.ErrorLogger.Print(err)
+			return
+		}
+
+		override, err := oncall.CreateOverride(tui.Client, ids[role], userID, start, end)
+
+		if err != nil {
+			utils.ErrorLogger.Print(err)
+			return
+		}
+
+		utils.InfoLogger.Printf("Override %s created for the %s schedule", override.ID, role)
+		tui.Pages.RemovePage(OncallOverridePageTitle)
+		tui.SeedOncallUI()
+	})
+
+	form.AddButton("Cancel", func() {
+		tui.Pages.RemovePage(OncallOverridePageTitle)
+		tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
+		tui.Footer.SetText(FooterTextOncall)
+	})
+
+	form.
+		SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderAttributes(tcell.AttrDim).
+		SetBorderPadding(1, 1, 1, 1).
+		SetTitle(fmt.Sprintf(TitleFmt, OncallOverrideTitle))
+
+	tui.Pages.AddAndSwitchToPage(OncallOverridePageTitle, form, true)
+	tui.Footer.SetText(FooterText)
+}
+
+// overridesPeriod is how far ahead the overrides are listed, as kite oncall override list does by default.
+const overridesPeriod = time.Hour * 24 * 30
+
+// InitOverridesUI initializes a TUI table page with the upcoming overrides of the configured schedules.
+func (tui *TUI) InitOverridesUI() {
+	org := tui.Config.Organization()
+	now := time.Now()
+
+	utils.InfoLogger.Print("GET: fetching the upcoming overrides")
+	overrides, err := oncall.ListOverrides(tui.Client, org, org.ScheduleIDs(), now, now.Add(overridesPeriod))
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+		return
+	}
+
+	headers := []string{"ID", "Schedule", "User", "Start", "End"}
+
+	var data [][]string
+
+	for _, o := range overrides {
+		data = append(data, []string{o.ID, o.Schedule, o.User, utils.DisplayTimestamp(o.Start), utils.DisplayTimestamp(o.End)})
+	}
+
+	tui.OverridesTable = tui.InitTable(headers, data, true, true, OncallOverridesTitle)
+
+	for i, o := range overrides {
+		tui.OverridesTable.GetCell(i+1, 0).SetReference(o)
+	}
+
+	if len(overrides) == 0 {
+		utils.InfoLogger.Print("No upcoming overrides")
+	}
+
+	tui.Pages.AddAndSwitchToPage(OncallOverridesPageTitle, tui.OverridesTable, true)
+	tui.Footer.SetText(FooterTextOverrides)
+}
+
+// DeleteSelectedOverride deletes the override selected in the overrides table.
+// It is not deleted if the schedule layers leave a gap in its time range, the override is then deleted with the kite oncall override delete --force command.
+func (tui *TUI) DeleteSelectedOverride() {
+	row, _ := tui.OverridesTable.GetSelection()
+	override, ok := tui.OverridesTable.GetCell(row, 0).GetReference().(oncall.Override)
+
+	if !ok {
+		return
+	}
+
+	err := oncall.DeleteOverride(tui.Client, override, false)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+		return
+	}
+
+	utils.InfoLogger.Printf("Override %s deleted", override.ID)
+	tui.SeedOncallUI()
+	tui.InitOverridesUI()
+}
+
+// InitEscalationChainUI initializes a TUI table page with the current on-call chain of the given service.
+// Each level of the escalation policy of the service is displayed with the users currently on-call for it.
+func (tui *TUI) InitEscalationChainUI(serviceID string) {
+	chain, err := oncall.ServiceEscalationChain(tui.Client, serviceID)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+		return
+	}
+
+	var data [][]string
+
+	for _, level := range chain.Levels {
+		targets := strings.Join(level.Targets, ", ")
+
+		if len(level.Users) == 0 {
+			data = append(data, []string{fmt.Sprint(level.Level), targets, "N/A", "N/A"})
+			continue
+		}
+
+		for _, user := range level.Users {
+			until := "N/A"
+
+			if user.End != "" {
+				until = utils.DisplayTimestamp(user.End)
+			}
+
+			data = append(data, []string{fmt.Sprint(level.Level), targets, user.Name, until})
+		}
+	}
+
+	headers := []string{"Level", "Targets", "Oncall", "To"}
+
+	tui.PreviousPage, _ = tui.Pages.GetFrontPage()
+	table := tui.InitTable(headers, data, false, false, fmt.Sprintf("[ %s %s ]", EscalationChainTableTitle, chain.EscalationPolicy))
+	tui.Pages.AddAndSwitchToPage(EscalationChainPageTitle, table, true)
+	tui.Footer.SetText(FooterText)
+}
+
+// InitNextOncallUI initializes TUI NextOncall table component.
+// It adds the returned table as a new TUI page view.
+func (tui *TUI) InitNextOncallUI(onCallData []oncall.OncallUser) {
+	tui.NextOncall = onCallData
+	headers, data := getOncallTableData(onCallData)
+	tui.NextOncallTable = tui.InitTable(headers, data, false, false, NextOncallTableTitle)
+	tui.Pages.AddPage(NextOncallPageTitle, tui.NextOncallTable, true, false)
+}
+
+// InitAllTeamsOncallUI initializes TUI AllTeamsOncall table component.
+// It adds the returned table as a new TUI page view.
+func (tui *TUI) InitAllTeamsOncallUI(onCallData []oncall.OncallUser) {
+	tui.AllTeamsOncall = onCallData
+	headers, data := getOncallTableData(onCallData)
+	tui.AllTeamsOncallTable = tui.InitTable(headers, data, false, false, AllTeamsOncallTableTitle)
+	tui.Pages.AddPage(AllTeamsOncallPageTitle, tui.AllTeamsOncallTable, true, false)
+}
+
+// OncallRoles returns the users on-call for the primary and secondary schedules of the given layer.
+func OncallRoles(layer oncall.OncallLayer, primaryScheduleID string, secondaryScheduleID string) (string, string) {
+	var primary, secondary string
+
+	for _, v := range layer.Users {
+		if v.ScheduleID == primaryScheduleID || (primary == "" && strings.Contains(v.OncallRole, "Primary")) {
+			primary = v.Name
+		}
+
+		if v.ScheduleID == secondaryScheduleID || (secondary == "" && strings.Contains(v.OncallRole, "Secondary")) {
+			secondary = v.Name
+		}
+	}
+
+	return primary, secondary
+}
+
+// getOncallTableData parses and returns tabular data for the given oncall data, i.e table headers and rows.
+func getOncallTableData(oncallData []oncall.OncallUser) ([]string, [][]string) {
+	var tableData [][]string
+
+	for _, v := range oncallData {
+		var data []string
+
+		if v.EscalationPolicy != "" {
+			data = append(data, v.EscalationPolicy)
+		} else {
+			data = append(data, "N/A")
+		}
+
+		if v.Name != "" {
+			data = append(data, v.Name)
+		} else {
+			data = append(data, "N/A")
+		}
+
+		if v.OncallRole != "" {
+			data = append(data, v.OncallRole)
+		} else {
+			data = append(data, "N/A")
+		}
+
+		if v.Start != "" {
+			data = append(data, utils.DisplayTimestamp(v.Start))
+		} else {
+			data = append(data, "N/A")
+		}
+
+		if v.End != "" {
+			data = append(data, utils.DisplayTimestamp(v.End))
+		} else {
+			data = append(data, "N/A")
+		}
+
+		tableData = append(tableData, data)
+	}
+
+	headers := []string{"Escalation Policy", "Name", "Oncall Role", "From", "To"}
+
+	return headers, tableData
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/gdamore/tcell/v2"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	oncall "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// InitRespondersUI initializes the responder picker of the given incident.
+// It lists the users and escalation policies of the current on-call data, selecting one requests it to respond to the incident.
+func (tui *TUI) InitRespondersUI(incidentID string) {
+	if tui.ResponderTargets == nil {
+		tui.ResponderTargets = tui.responderTargets()
+	}
+
+	if len(tui.ResponderTargets) == 0 {
+		utils.ErrorLogger.Print("Nobody is currently on-call")
+		return
+	}
+
+	list := tview.NewList()
+
+	for _, target := range tui.ResponderTargets {
+		target := target
+
+		list.AddItem(target.Name, target.Role, 0, func() {
+			utils.InfoLogger.Printf("POST: requesting %s to respond to incident %s", target.Name, incidentID)
+			_, err := pdcli.RequestResponders(tui.Client, incidentID, pdcli.DefaultResponderMessage, []pdApi.APIObject{target.Reference()})
+
+			if err != nil {
+				utils.ErrorLogger.Print(err)
+				return
+			}
+
+			utils.InfoLogger.Printf("Requested %s to respond to incident %s", target.Name, incidentID)
+		})
+	}
+
+	list.
+		SetSelectedTextColor(tcell.ColorBlack).
+		SetSelectedBackgroundColor(TableTitleColor).
+		SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderPadding(1, 1, 1, 1).
+		SetBorderAttributes(tcell.AttrDim).
+		SetTitle(fmt.Sprintf(TitleFmt, RespondersTitle))
+
+	tui.PreviousPage, _ = tui.Pages.GetFrontPage()
+	tui.Pages.AddAndSwitchToPage(RespondersPageTitle, list, true)
+	tui.Footer.SetText(FooterTextResponders)
+}
+
+// responderTargets returns the responder request targets of the on-call data.
+// The on-call data displayed by kite oncall is used, it is fetched when the picker is opened from the alerts.
+func (tui *TUI) responderTargets() []oncall.ResponderTarget {
+	now := time.Now()
+
+	if tui.OncallLayers != nil {
+		return oncall.ResponderTargets(tui.OncallLayers, tui.AllTeamsOncall, now)
+	}
+
+	utils.InfoLogger.Print("GET: fetching on-call data")
+	targets, err := oncall.OncallResponderTargets(tui.Client, tui.Config.Organization(), now)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+		return nil
+	}
+
+	return targets
+}
@@ -1,6 +1,10 @@
 package ui
 
 import (
+	"strings"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
@@ -8,62 +12,36 @@ import (
 
 // SeedAckIncidentsUI fetches acknlowedged incidents and initializes a TUI table/page component.
 func (tui *TUI) SeedAckIncidentsUI() {
-	var ackIncidents [][]string
-
 	utils.InfoLogger.Printf("Incidents status set to: %s", constants.StatusAcknowledged)
 	tui.IncidentOpts.Statuses = []string{constants.StatusAcknowledged}
 
 	// Fetch new incidents via PD API
 	utils.InfoLogger.Print("GET: fetching acknowledged incidents")
-	incidents, err := pdcli.GetIncidents(tui.Client, &tui.IncidentOpts)
+	incidents, err := tui.fetchIncidents()
 
 	if err != nil {
 		utils.ErrorLogger.Print(err)
 	}
 
-	for _, i := range incidents {
-		// Added columns 'Id', 'Title', 'Status', 'Service', 'Assigned To' to acknlowedged incidents table
-		assignment := i.Assignments[0]
-		incident := []string{i.Id, i.Title, i.Urgency, i.Status, i.Service.Summary, assignment.Assignee.Summary}
-		ackIncidents = append(ackIncidents, incident)
-	}
-
-	tui.Incidents = ackIncidents
-
-	tui.InitIncidentsUI(tui.Incidents, AckIncidentsTableTitle, AckIncidentsPageTitle, false)
+	tui.InitIncidentsUI(incidents, AckIncidentsTableTitle, AckIncidentsPageTitle, false)
 	tui.Footer.SetText(FooterTextAckIncidents)
 	tui.Pages.SwitchToPage(AckIncidentsPageTitle)
 }
 
 // SeedIncidentsUI fetches trigerred incidents and initializes a TUI table/page component.
 func (tui *TUI) SeedIncidentsUI() {
-	var incidentsData [][]string
-
 	utils.InfoLogger.Printf("Incidents status set to: %s", constants.StatusTriggered)
 	tui.IncidentOpts.Statuses = []string{constants.StatusTriggered}
 
-	// Override incidents limit when viewing triggered incidents
-	utils.InfoLogger.Printf("Incidents limit set to: %d", constants.TrigerredIncidentsLimit)
-	tui.IncidentOpts.Limit = constants.TrigerredIncidentsLimit
-
 	// Fetch new incidents via PD API
 	utils.InfoLogger.Print("GET: fetching incidents")
-	incidents, err := pdcli.GetIncidents(tui.Client, &tui.IncidentOpts)
+	incidents, err := tui.fetchIncidents()
 
 	if err != nil {
 		utils.ErrorLogger.Print(err)
 	}
 
-	for _, i := range incidents {
-		// Added columns 'Id', 'Title', 'Status', 'Service', 'Assigned To' to triggered incidents table
-		assignment := i.Assignments[0]
-		incident := []string{i.Id, i.Title, i.Urgency, i.Status, i.Service.Summary, assignment.Assignee.Summary}
-		incidentsData = append(incidentsData, incident)
-	}
-
-	tui.Incidents = incidentsData
-
-	tui.InitIncidentsUI(tui.Incidents, IncidentsTableTitle, IncidentsPageTitle, true)
+	tui.InitIncidentsUI(incidents, IncidentsTableTitle, IncidentsPageTitle, true)
 	tui.Footer.SetText(FooterTextIncidents)
 }
 
@@ -75,7 +53,10 @@ func (tui *TUI) SeedAlertsUI() {
 	// Refresh triggered alerts
 	pdcli.TrigerredAlerts = []pdcli.Alert{}
 
-	if tui.AssignedTo == tui.Username {
+	if len(tui.Statuses) > 0 {
+		utils.InfoLogger.Printf("Incidents status set to: %s", strings.Join(tui.Statuses, ", "))
+		tui.IncidentOpts.Statuses = tui.Statuses
+	} else if tui.AssignedTo == tui.Username {
 		utils.InfoLogger.Printf("Incidents status set to: %s", constants.StatusAcknowledged)
 		tui.IncidentOpts.Statuses = []string{constants.StatusAcknowledged}
 	} else {
@@ -85,7 +66,7 @@ func (tui *TUI) SeedAlertsUI() {
 
 	// Fetch new incidents via PD API
 	utils.InfoLogger.Print("GET: fetching incidents")
-	incidents, err := pdcli.GetIncidents(tui.Client, &tui.IncidentOpts)
+	incidents, err := tui.fetchIncidents()
 
 	if err != nil {
 		utils.ErrorLogger.Print(err)
@@ -103,7 +84,58 @@ func (tui *TUI) SeedAlertsUI() {
 		alerts = append(alerts, incidentAlerts...)
 	}
 
+	alerts, err = pdcli.FilterAlerts(alerts, tui.Filters)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+	}
+
+	err = pdcli.SortAlerts(alerts, tui.SortBy)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+	}
+
 	tui.Alerts = alerts
 
 	tui.InitAlertsUI(tui.Alerts, AlertsTableTitle, AlertsPageTitle)
 }
+
+// StartAutoRefresh refreshes the alerts at the given interval, while the alerts page is displayed.
+func (tui *TUI) StartAutoRefresh(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	utils.InfoLogger.Printf("Refreshing alerts every %s", interval)
+
+	go func() {
+		for range time.Tick(interval) {
+			tui.App.QueueUpdateDraw(func() {
+				if title, _ := tui.Pages.GetFrontPage(); title == AlertsPageTitle {
+					utils.InfoLogger.Print("Refreshing alerts...")
+					tui.SeedAlertsUI()
+					tui.InitAlertsSecondaryView()
+				}
+			})
+		}
+	}()
+}
+
+// fetchIncidents fetches the incidents for the current incident options.
+// The suppressed incidents are only included when they are toggled to be shown.
+func (tui *TUI) fetchIncidents() ([]pagerduty.Incident, error) {
+	incidents, hidden, err := pdcli.GetAllIncidents(tui.Client, &tui.IncidentOpts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tui.HiddenIncidents = len(hidden)
+
+	if tui.ShowSuppressed {
+		incidents = append(incidents, hidden...)
+	}
+
+	return incidents, nil
+}
@@ -2,8 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"path"
 	"strconv"
-	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
@@ -18,10 +18,9 @@ func ViewAlertSOP(tui *TUI, URL string) {
 		SetChangedFunc(func() {
 			tui.App.Draw()
 		})
-	utils.FetchHTMLContent(URL, textView)
-	readmePath := strings.Split(utils.GetReadmePath(URL), "/")
-	name := readmePath[len(readmePath)-1]
-	textView.Highlight("0").SetBorder(true).SetTitle(fmt.Sprintf(" %s ", name))
+	freshness := utils.FetchHTMLContent(tui.Config, URL, textView)
+	name := sopName(URL)
+	textView.Highlight("0").SetBorder(true).SetTitle(sopTitle(name, freshness))
 	AddSOPSlide(name, textView, tui)
 	// Input Handling
 	textView.SetDoneFunc(func(key tcell.Key) {
@@ -30,8 +29,8 @@ func ViewAlertSOP(tui *TUI, URL string) {
 			index, _ := strconv.Atoi(currentSelection[0])
 			if key == tcell.KeyEnter {
 				url := textView.GetRegionText(currentSelection[0])
-				utils.FetchHTMLContent(url, textView)
-				fmt.Println(url)
+				freshness := utils.FetchHTMLContent(tui.Config, url, textView)
+				textView.SetTitle(sopTitle(sopName(url), freshness))
 			}
 			if key == tcell.KeyTab {
 				index = (index + 1) % tui.NumLinks
@@ -45,3 +44,17 @@ func ViewAlertSOP(tui *TUI, URL string) {
 	})
 
 }
+
+// sopName returns the name of the SOP document of the URL, e.g. ClusterDown.md.
+func sopName(URL string) string {
+	if _, _, _, docPath, err := utils.ParseGitHubURL(URL); err == nil {
+		return path.Base(docPath)
+	}
+
+	return URL
+}
+
+// sopTitle returns the title of the SOP view, with the freshness of the SOP, e.g. " ClusterDown.md (cached 12m ago) ".
+func sopTitle(name string, freshness string) string {
+	return fmt.Sprintf(" %s (%s) ", name, freshness)
+}
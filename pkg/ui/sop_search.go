@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// sopSearchLimit is the maximum number of SOPs listed by the search tab
+const sopSearchLimit = 50
+
+// ViewSOPSearch opens the SOP search tab, the query is searched right away when not empty.
+// The selected SOP is opened in a SOP slide.
+func ViewSOPSearch(tui *TUI, query string) {
+	input := tview.NewInputField().
+		SetLabel("Search: ").
+		SetText(query).
+		SetFieldBackgroundColor(tcell.ColorDefault)
+
+	input.SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderAttributes(tcell.AttrDim).
+		SetTitle(fmt.Sprintf(TitleFmt, SOPSearchTitle))
+
+	results := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	results.SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderAttributes(tcell.AttrDim)
+
+	snippets := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+
+	snippets.SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderAttributes(tcell.AttrDim).
+		SetBorderPadding(0, 0, 1, 1)
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(input, 3, 0, true).
+		AddItem(results, 0, 3, false).
+		AddItem(snippets, 0, 1, false)
+
+	var found []sop.Result
+
+	search := func() {
+		query := strings.TrimSpace(input.GetText())
+
+		if query == "" {
+			return
+		}
+
+		results.Clear()
+		snippets.Clear()
+		results.SetTitle(" Searching... ")
+
+		// The index is loaded once, its documents may be downloaded from GitHub
+		index := tui.SOPIndex
+
+		go func() {
+			var err error
+
+			if index == nil {
+				index, err = sop.LoadIndex(tui.Config, false)
+			}
+
+			tui.App.QueueUpdateDraw(func() {
+				if err != nil {
+					results.SetTitle(" No results ")
+					utils.ErrorLogger.Printf("Cannot search the SOPs: %v", err)
+					return
+				}
+
+				tui.SOPIndex = index
+				found = index.Search(query, sopSearchLimit)
+				setSOPSearchResults(results, found)
+				results.SetTitle(fmt.Sprintf(" %d results in %s ", len(found), tview.Escape(index.Source)))
+
+				if len(found) > 0 {
+					results.Select(1, 0)
+					tui.App.SetFocus(results)
+				}
+			})
+		}()
+	}
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			search()
+		case tcell.KeyTab:
+			tui.App.SetFocus(results)
+		}
+	})
+
+	results.SetSelectionChangedFunc(func(row int, column int) {
+		snippets.Clear()
+
+		if row > 0 && row <= len(found) {
+			fmt.Fprint(snippets, tview.Escape(strings.Join(found[row-1].Snippets, "\n")))
+		}
+	})
+
+	results.SetSelectedFunc(func(row int, column int) {
+		if row > 0 && row <= len(found) {
+			utils.InfoLogger.Printf("Opening SOP %s in a new tab", found[row-1].Path)
+			ViewAlertSOP(tui, found[row-1].URL())
+		}
+	})
+
+	results.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab || event.Rune() == '/' {
+			tui.App.SetFocus(input)
+			return nil
+		}
+
+		return event
+	})
+
+	AddSOPSlide(SOPSearchTabTitle, layout, tui)
+
+	if query != "" {
+		search()
+	}
+}
+
+// setSOPSearchResults lists the SOPs found in the results table.
+func setSOPSearchResults(table *tview.Table, results []sop.Result) {
+	for i, header := range []string{"TITLE", "PATH"} {
+		table.SetCell(0, i, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	for i, result := range results {
+		table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(result.Title)).SetExpansion(1))
+		table.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(result.Path)).SetExpansion(1))
+	}
+}
@@ -32,7 +32,7 @@ func InitKiteTab(tui *TUI, layout *tview.Flex) *TerminalTab {
 }
 
 // Creates and returns a SOP tab
-func InitSOPTab(name string, layout *tview.TextView, tui *TUI) *TerminalTab {
+func InitSOPTab(name string, layout tview.Primitive, tui *TUI) *TerminalTab {
 	TotalPageCount += 1
 	tui.TerminalUIRegionIDs = append(tui.TerminalUIRegionIDs, TotalPageCount)
 	index := len(tui.TerminalTabs)
@@ -151,7 +151,7 @@ func AddNewSlide(tui *TUI, name string, command string, args []string, isCluster
 }
 
 // Adds a SOP slide to the end of currently present slides
-func AddSOPSlide(name string, textView *tview.TextView, tui *TUI) {
+func AddSOPSlide(name string, textView tview.Primitive, tui *TUI) {
 	if len(tui.TerminalTabs) < 9 {
 		for i, tab := range tui.TerminalTabs {
 			if tab.primitive != nil && tab.title == name {
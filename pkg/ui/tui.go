@@ -3,11 +3,15 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/PagerDuty/go-pagerduty"
 	"github.com/gdamore/tcell/v2"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	oncall "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/rivo/tview"
 )
@@ -21,6 +25,7 @@ type TUI struct {
 	IncidentsTable      *tview.Table
 	NextOncallTable     *tview.Table
 	AllTeamsOncallTable *tview.Table
+	OverridesTable      *tview.Table
 	Pages               *tview.Pages
 	SecondaryWindow     *tview.TextView
 	LogWindow           *tview.TextView
@@ -31,6 +36,8 @@ type TUI struct {
 
 	// API related
 	Client       client.PagerDutyClient
+	Config       *config.Config
+	User         *pagerduty.User
 	IncidentOpts pagerduty.ListIncidentsOptions
 	Alerts       []pdcli.Alert
 
@@ -39,17 +46,48 @@ type TUI struct {
 	Incidents         [][]string
 	AckIncidents      []string
 	AssignedTo        string
+	Assignment        string
 	Username          string
 	Role              string
 	Columns           string
 	ClusterID         string
 	ClusterName       string
+	ServiceID         string
+	IncidentID        string
+	PreviousPage      string
 	CurrentOnCallPage int
+	DefaultOnCallPage int
+	OnCallLayerCount  int
+
+	// On-call window
+	OncallSince time.Time
+	OncallUntil time.Time
+	OncallAt    time.Time
+
+	// On-call data
+	OncallLayers   []oncall.OncallLayer
+	NextOncall     []oncall.OncallUser
+	AllTeamsOncall []oncall.OncallUser
+
+	// Responder requests
+	ResponderTargets []oncall.ResponderTarget
+
+	// Saved views
+	ActiveView string
+	Statuses   []string
+	Filters    []string
+	SortBy     string
+
+	// Suppressed incidents
+	ShowSuppressed  bool
+	HiddenIncidents int
 
 	// SOP Related
-	SOPLink  string
-	NumLinks int
-	SOPView  *tview.TextView
+	SOPLink   string
+	NumLinks  int
+	SOPView   *tview.TextView
+	AlertName string
+	SOPIndex  *sop.Index
 
 	// Multi-Window Terminals Related
 	TerminalLayout      *tview.Flex
@@ -65,8 +103,21 @@ type TUI struct {
 // InitAlertsUI initializes TUI table component.
 // It adds the returned table as a new TUI page view.
 func (tui *TUI) InitAlertsUI(alerts []pdcli.Alert, tableTitle string, pageTitle string) {
-	headers, data := pdcli.GetTableData(alerts, tui.Columns)
+	headers, data, err := pdcli.GetTableData(alerts, tui.Columns)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+	}
+
 	tui.Table = tui.InitTable(headers, data, true, false, tableTitle)
+
+	// Reference the alert from its table row, the alert is then retrieved on selection
+	for i, alert := range alerts {
+		if cell := tui.Table.GetCell(i+1, 0); cell != nil {
+			cell.SetReference(alert)
+		}
+	}
+
 	tui.SetAlertsTableEvents(alerts)
 
 	if len(alerts) == 0 && tui.Username == tui.AssignedTo {
@@ -85,14 +136,20 @@ func (tui *TUI) InitAlertsUI(alerts []pdcli.Alert, tableTitle string, pageTitle
 
 // InitIncidentsUI initializes TUI table component.
 // It adds the returned table as a new TUI page view.
-func (tui *TUI) InitIncidentsUI(incidents [][]string, tableTitle string, pageTitle string, isAckTable bool) {
-	incidentHeaders := []string{"INCIDENT ID", "NAME", "SEVERITY", "STATUS", "SERVICE", "ASSIGNED TO"}
+func (tui *TUI) InitIncidentsUI(incidents []pagerduty.Incident, tableTitle string, pageTitle string, isAckTable bool) {
+	incidentHeaders, data, err := pdcli.GetTableData(pdcli.IncidentRows(incidents), pdcli.DefaultIncidentColumns)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+	}
+
+	tui.Incidents = data
 
 	if isAckTable {
-		tui.IncidentsTable = tui.InitTable(incidentHeaders, incidents, true, true, tableTitle)
+		tui.IncidentsTable = tui.InitTable(incidentHeaders, tui.Incidents, true, true, tableTitle)
 		tui.SetIncidentsTableEvents()
 	} else {
-		tui.IncidentsTable = tui.InitTable(incidentHeaders, incidents, true, true, tableTitle)
+		tui.IncidentsTable = tui.InitTable(incidentHeaders, tui.Incidents, true, true, tableTitle)
 		tui.SetAckTableEvents()
 	}
 
@@ -102,31 +159,63 @@ func (tui *TUI) InitIncidentsUI(incidents [][]string, tableTitle string, pageTit
 }
 
 func (tui *TUI) InitAlertsSecondaryView() {
-	tui.SecondaryWindow.SetText(
-		fmt.Sprintf("Logged in user: %s\n\nViewing alerts assigned to: %s\n\nPagerDuty role: %s",
-			tui.Username,
-			tui.AssignedTo,
-			tui.Role)).
-		SetTextColor(InfoTextColor)
+	secondaryViewText := fmt.Sprintf("Logged in user: %s\n\nViewing alerts assigned to: %s\n\nPagerDuty role: %s",
+		tui.Username,
+		tui.AssignedTo,
+		tui.Role)
+
+	if tui.Config != nil {
+		secondaryViewText += fmt.Sprintf("\n\nProfile: %s", tui.Config.Profile())
+	}
+
+	if tui.ActiveView != "" {
+		secondaryViewText += fmt.Sprintf("\n\nView: %s", tui.ActiveView)
+	}
+
+	if tui.HiddenIncidents > 0 {
+		if tui.ShowSuppressed {
+			secondaryViewText += fmt.Sprintf("\n\n%d suppressed incidents shown (press 'H' to hide)", tui.HiddenIncidents)
+		} else {
+			secondaryViewText += fmt.Sprintf("\n\n%d hidden (press 'H' to reveal)", tui.HiddenIncidents)
+		}
+	}
+
+	tui.SecondaryWindow.SetText(secondaryViewText).SetTextColor(InfoTextColor)
 }
 
 func (tui *TUI) InitAlertDataSecondaryView() {
-	var secondaryViewText string
+	tui.SecondaryWindow.SetText(tui.alertPrompt(tui.ClusterName)).SetTextColor(PromptTextColor)
+}
 
-	PromptClusterLogin := fmt.Sprintf("Press 'Y' to log into the cluster: %s\n", tui.ClusterName)
-	PromptServiceLogs := "Press 'L' to view service logs"
+// alertPrompt returns the prompts of the actions on the selected alert and its cluster.
+func (tui *TUI) alertPrompt(clusterName string) string {
+	return fmt.Sprintf("Press 'Y' to log into the cluster: %s\n%s\nPress 'L' to view service logs\nPress 'E' to view the on-call chain\nPress 'P' to request responders\nPress 'C' to view the cluster history", clusterName, tui.sopPrompt())
+}
 
-	secondaryViewText = PromptClusterLogin + PromptServiceLogs
-	tui.SecondaryWindow.SetText(secondaryViewText).SetTextColor(PromptTextColor)
+// sopPrompt returns the prompt of the SOP of the selected alert, it explains why the SOP can't be viewed in kite.
+func (tui *TUI) sopPrompt() string {
+	if tui.SOPLink == "" || tui.SOPLink == "<nil>" {
+		return "No SOP mentioned for the alert, press '/' to search the SOPs"
+	}
+
+	if !tui.Config.HasSOPs() {
+		return "No GitHub token or local SOP clone configured, open the SOP in a browser"
+	}
+
+	return "Press 'S' to view the SOP"
 }
 
 func (tui *TUI) InitOnCallSecondaryView(user string, primary string, secondary string) {
-	tui.SecondaryWindow.SetText(
-		fmt.Sprintf("Logged in user: %s\nCurrent Primary on-call: %s\nCurrent Secondary on-call: %s",
-			user,
-			primary,
-			secondary),
-	)
+	secondaryViewText := fmt.Sprintf("Logged in user: %s\nCurrent Primary on-call: %s\nCurrent Secondary on-call: %s",
+		user,
+		primary,
+		secondary)
+
+	if !tui.OncallAt.IsZero() {
+		secondaryViewText += fmt.Sprintf("\nViewing on-call at: %s", tui.OncallAt.In(utils.DisplayLocation).Format("Mon 01-02-2006 15:04 MST"))
+	}
+
+	tui.SecondaryWindow.SetText(secondaryViewText)
 }
 
 // initFooter initializes the footer text depending on the page currently visible.
@@ -137,6 +226,9 @@ func (t *TUI) initFooter() {
 	case AlertsPageTitle:
 		t.Footer.SetText(FooterTextAlerts)
 
+	case HandoverPageTitle:
+		t.Footer.SetText(FooterTextHandover)
+
 	default:
 		t.Footer.SetText(FooterText)
 	}
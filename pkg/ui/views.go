@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// InitViewsUI initializes the view picker list.
+// It lists the acknowledged and trigerred incidents views followed by the saved views from the config file.
+func (tui *TUI) InitViewsUI() {
+	list := tview.NewList()
+
+	list.AddItem("Acknowledged Incidents", "Incidents acknowledged by the assignee", '1', func() {
+		utils.InfoLogger.Print("Switching to acknowledged incidents view")
+		tui.SeedAckIncidentsUI()
+
+		if len(tui.Incidents) == 0 {
+			utils.InfoLogger.Printf("No acknowledged incidents assigned found")
+		}
+
+		tui.Pages.SwitchToPage(AckIncidentsPageTitle)
+	})
+
+	list.AddItem("Trigerred Incidents", "Incidents waiting to be acknowledged", '2', func() {
+		utils.InfoLogger.Print("Switching to incidents view")
+		tui.SeedIncidentsUI()
+
+		if len(tui.Incidents) == 0 {
+			utils.InfoLogger.Printf("No trigerred incidents assigned to found")
+		}
+
+		tui.Pages.SwitchToPage(IncidentsPageTitle)
+	})
+
+	if tui.Config != nil {
+		for i, view := range tui.Config.Views {
+			view := view
+
+			var shortcut rune
+
+			if i < 7 {
+				shortcut = rune('3' + i)
+			}
+
+			list.AddItem(view.Name, describeView(view), shortcut, func() {
+				tui.ApplyView(view)
+			})
+		}
+	}
+
+	list.
+		SetSelectedTextColor(tcell.ColorBlack).
+		SetSelectedBackgroundColor(TableTitleColor).
+		SetBorder(true).
+		SetBorderColor(BorderColor).
+		SetBorderPadding(1, 1, 1, 1).
+		SetBorderAttributes(tcell.AttrDim).
+		SetTitle(fmt.Sprintf(TitleFmt, ViewsTableTitle))
+
+	tui.Pages.AddAndSwitchToPage(ViewsPageTitle, list, true)
+	tui.Footer.SetText(FooterTextViews)
+}
+
+// ApplyView re-fetches the alerts using the given saved view.
+func (tui *TUI) ApplyView(view config.View) {
+	assignment := tui.Assignment
+
+	if view.AssignedTo != "" {
+		assignment = view.AssignedTo
+	}
+
+	opts, assignedTo, err := pdcli.ViewOptions(view, assignment, tui.User, tui.Config)
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+		return
+	}
+
+	if view.Columns != "" {
+		columns := tui.Config.Columns(view.Columns)
+
+		_, err = pdcli.ParseColumns(columns)
+
+		if err != nil {
+			utils.ErrorLogger.Print(err)
+			return
+		}
+
+		tui.Columns = columns
+	}
+
+	utils.InfoLogger.Printf("Switching to saved view: %s", view.Name)
+
+	tui.IncidentOpts = opts
+	tui.AssignedTo = assignedTo
+	tui.ActiveView = view.Name
+	tui.Statuses = opts.Statuses
+	tui.Filters = view.Filters
+	tui.SortBy = view.Sort
+
+	tui.SeedAlertsUI()
+	tui.InitAlertsSecondaryView()
+}
+
+// describeView returns a short summary of the given saved view.
+func describeView(view config.View) string {
+	var summary []string
+
+	if view.AssignedTo != "" {
+		summary = append(summary, "assigned to: "+view.AssignedTo)
+	}
+
+	if len(view.Statuses) > 0 {
+		summary = append(summary, "statuses: "+strings.Join(view.Statuses, ", "))
+	}
+
+	if len(view.Urgencies) > 0 {
+		summary = append(summary, "urgencies: "+strings.Join(view.Urgencies, ", "))
+	}
+
+	if len(view.Filters) > 0 {
+		summary = append(summary, "filters: "+strings.Join(view.Filters, ", "))
+	}
+
+	if view.Sort != "" {
+		summary = append(summary, "sort: "+view.Sort)
+	}
+
+	return strings.Join(summary, " | ")
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of the kite commands
+const (
+	// ExitFailure is the exit code of the errors without a specific exit code
+	ExitFailure = 1
+
+	// ExitUsage is the exit code of the invalid flags, arguments or input values
+	ExitUsage = 2
+
+	// ExitAuth is the exit code of the credentials rejected by PagerDuty or GitHub
+	ExitAuth = 3
+
+	// ExitNoInput is the exit code of a missing value which can't be prompted for, the standard input not being a terminal
+	ExitNoInput = 4
+)
+
+// ExitError is an error exiting kite with a specific exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// NewExitError returns an error exiting kite with the given code.
+func NewExitError(code int, format string, a ...interface{}) error {
+	return &ExitError{Code: code, Err: fmt.Errorf(format, a...)}
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the error.
+func ExitCode(err error) int {
+	var exitError *ExitError
+
+	if errors.As(err, &exitError) {
+		return exitError.Code
+	}
+
+	return ExitFailure
+}
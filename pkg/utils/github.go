@@ -1,18 +1,165 @@
 package utils
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/google/go-github/v50/github"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
 	"golang.org/x/oauth2"
 )
 
-func GetGHReadme(owner, repo, path string) (string, error) {
-	cfg, err := config.Load()
+// ErrNoGitHubToken is returned when a document is fetched from GitHub without a GitHub token.
+var ErrNoGitHubToken = errors.New("no GitHub token is configured, run the 'kite login' command to view the SOPs in kite")
+
+// SOP sources, where the content of a SOP is read from
+const (
+	SOPSourceLocal  = "local"
+	SOPSourceGitHub = "github"
+	SOPSourceCache  = "cache"
+)
+
+// SOPDocument is the content of a SOP and where it was read from.
+type SOPDocument struct {
+	Content string
+
+	// Source is where the content was read from, i.e. SOPSourceLocal, SOPSourceGitHub or SOPSourceCache
+	Source string
+
+	// FetchedAt is when the content was last downloaded or validated with GitHub
+	FetchedAt time.Time
+
+	// Stale explains why the cached content is older than the max age, e.g. offline
+	Stale string
+}
+
+// Freshness describes where the SOP was read from and how old it is, e.g. "local clone", "cached 12m ago" or "offline, cached 3h ago".
+func (d *SOPDocument) Freshness(now time.Time) string {
+	switch {
+	case d.Source == SOPSourceLocal:
+		return "local clone"
+	case d.Stale != "":
+		return fmt.Sprintf("%s, cached %s", d.Stale, RelativeTime(d.FetchedAt, now))
+	case d.Source == SOPSourceCache:
+		return "cached " + RelativeTime(d.FetchedAt, now)
+	default:
+		return "fetched " + RelativeTime(d.FetchedAt, now)
+	}
+}
+
+// GetGHReadme returns the content of a document of the default branch of a GitHub repository, read from the cache or fetched with the configured GitHub token.
+func GetGHReadme(cfg *config.Config, owner, repo, path string) (string, error) {
+	document, err := getCachedDocument(cfg, owner, repo, "", path)
 	if err != nil {
 		return "", err
 	}
+
+	return document.Content, nil
+}
+
+// GetSOP returns the SOP of the URL, i.e. https://github.com/<owner>/<repo>/blob/<ref>/<path>.md.
+// It is read from the local clone of the SOP repository when configured, the cache and GitHub are only the fallbacks.
+func GetSOP(cfg *config.Config, URL string) (*SOPDocument, error) {
+	owner, repo, ref, path, err := ParseGitHubURL(URL)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := cfg.SOPPath()
+
+	if clone == "" {
+		return getCachedDocument(cfg, owner, repo, ref, path)
+	}
+
+	content, localErr := sop.ReadLocal(clone, owner, repo, path)
+	if localErr == nil {
+		return &SOPDocument{Content: content, Source: SOPSourceLocal}, nil
+	}
+
+	document, err := getCachedDocument(cfg, owner, repo, ref, path)
+	if err != nil {
+		return nil, fmt.Errorf("%v, and from the cache or GitHub: %v", localErr, err)
+	}
+
+	return document, nil
+}
+
+// getCachedDocument returns a document of a GitHub repository from the cache, validated with GitHub once older than the max age.
+// The cached document is returned stale when it can't be validated, e.g. offline or when GitHub can't be reached.
+func getCachedDocument(cfg *config.Config, owner, repo, ref, path string) (*SOPDocument, error) {
+	cache, err := sop.NewCache()
+	if err != nil {
+		return nil, err
+	}
+
+	// A document which can't be read from the cache is fetched again
+	cached, _ := cache.Get(owner, repo, ref, path)
+
+	stale := func(reason string) *SOPDocument {
+		return &SOPDocument{Content: cached.Content, Source: SOPSourceCache, FetchedAt: cached.FetchedAt, Stale: reason}
+	}
+
+	if cached != nil && time.Since(cached.FetchedAt) < cfg.SOPMaxAge() {
+		return &SOPDocument{Content: cached.Content, Source: SOPSourceCache, FetchedAt: cached.FetchedAt}, nil
+	}
+
+	if cfg.Offline() {
+		if cached == nil {
+			return nil, fmt.Errorf("the document is not cached, it can't be fetched from GitHub offline")
+		}
+
+		return stale("offline"), nil
+	}
+
+	if !cfg.HasGitHub() {
+		if cached == nil {
+			return nil, ErrNoGitHubToken
+		}
+
+		return stale("no GitHub token"), nil
+	}
+
+	etag := ""
+
+	if cached != nil {
+		etag = cached.ETag
+	}
+
+	content, etag, modified, err := getGitHubDocument(cfg, owner, repo, ref, path, etag)
+	if err != nil {
+		if cached == nil {
+			return nil, err
+		}
+
+		return stale("GitHub unreachable"), nil
+	}
+
+	if modified {
+		cached = &sop.CachedDocument{Content: content, ETag: etag}
+	}
+
+	cached.FetchedAt = time.Now()
+
+	// The document is still displayed when it can't be cached
+	_ = cache.Put(owner, repo, ref, path, cached)
+
+	return &SOPDocument{Content: cached.Content, Source: SOPSourceGitHub, FetchedAt: cached.FetchedAt}, nil
+}
+
+// getGitHubDocument fetches the raw content of a document of a GitHub repository with the configured GitHub token.
+// The document of the default branch is fetched when the ref is empty.
+// The request is conditional when an ETag is given, the document is not modified when GitHub still returns the same ETag.
+func getGitHubDocument(cfg *config.Config, owner, repo, ref, path, etag string) (content string, newETag string, modified bool, err error) {
+	if !cfg.HasGitHub() {
+		return "", "", false, ErrNoGitHubToken
+	}
+
 	// Use Backgound Context
 	ctx := context.Background()
 
@@ -24,16 +171,49 @@ func GetGHReadme(owner, repo, path string) (string, error) {
 
 	// Create GitHub Client
 	client := github.NewClient(tc)
-	options := github.RepositoryContentGetOptions{}
 
-	// Get Contents Accordingly
-	content, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &options)
+	u := fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, (&url.URL{Path: path}).String())
+
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+
+	req, err := client.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
-		return "", err
+		return "", "", false, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.raw")
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	var body bytes.Buffer
+
+	resp, err := client.Do(ctx, req, &body)
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return "", etag, false, nil
 	}
-	decodedContent, err := content.GetContent()
 	if err != nil {
-		return "", err
+		return "", "", false, gitHubError(owner, repo, resp, err)
 	}
-	return decodedContent, nil
+
+	return body.String(), resp.Header.Get("ETag"), true, nil
+}
+
+// gitHubError explains the errors of the GitHub API caused by the GitHub token.
+func gitHubError(owner, repo string, resp *github.Response, err error) error {
+	if resp == nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("the GitHub token is rejected, run the 'kite login' command: %v", err)
+	case http.StatusNotFound:
+		return fmt.Errorf("the document is not found, or the GitHub token can't access the %s/%s repository: %v", owner, repo, err)
+	}
+
+	return err
 }
@@ -2,7 +2,9 @@ package utils
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/rivo/tview"
 	"golang.org/x/net/html"
 )
@@ -40,15 +42,24 @@ func TraverseHTMLDoc(n *html.Node, textView *tview.TextView) int {
 	return numLinks
 }
 
-func FetchHTMLContent(URL string, textView *tview.TextView) {
+// writeSOPFallback explains why the document can't be displayed and links its URL instead, e.g. to open it in a browser.
+func writeSOPFallback(URL string, err error, textView *tview.TextView) {
+	fmt.Fprintf(textView, "[yellow]The SOP can't be displayed in kite: %s\n\n[white]Open it in a browser:\n", tview.Escape(err.Error()))
+	fmt.Fprintf(textView, `["%d"][blue]%s[white][""]`, numLinks, tview.Escape(URL))
+	numLinks++
+}
+
+// FetchHTMLContent displays the SOP of the URL in the text view and returns its freshness, e.g. "cached 12m ago".
+func FetchHTMLContent(cfg *config.Config, URL string, textView *tview.TextView) string {
 	textView.Clear()
 	numLinks = 0
-	owner, repo, path := getGitHubMdURL(URL)
-	contents, err := GetGHReadme(owner, repo, path)
+	document, err := GetSOP(cfg, URL)
 	if (err) != nil {
 		ErrorLogger.Printf("Error while fetching readme contents. The error message was : %s", err)
+		writeSOPFallback(URL, err, textView)
+		return "unavailable"
 	}
-	body := ConvertMarkdownToHTML(contents)
+	body := ConvertMarkdownToHTML(document.Content)
 
 	// Parse the HTML file
 	doc, err := html.Parse(body)
@@ -56,6 +67,7 @@ func FetchHTMLContent(URL string, textView *tview.TextView) {
 		ErrorLogger.Printf("Error while fetching readme contents. The error message was : %s", err)
 	}
 	TraverseHTMLDoc(doc, textView)
+	return document.Freshness(time.Now())
 	// TODO : Work on Parsing Non Markdown Files
 	// doc, err := html.Parse(body)
 	// if err != nil {
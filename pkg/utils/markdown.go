@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"strings"
 
@@ -10,16 +11,25 @@ import (
 	"github.com/gomarkdown/markdown/parser"
 )
 
-func getGitHubMdURL(URL string) (owner, repo, path string) {
-	end := URL[len(URL)-3:]
-	host := URL[8:18]
-	if host == "github.com" && end == ".md" {
-		URL = strings.Replace(URL, "tree", "blob", -1)
-		owner, repo := GetOwnerAndRepoName(URL)
-		path := GetReadmePath(URL)
-		return owner, repo, path
+// gitHubURL is the prefix of the URLs of the GitHub repositories
+const gitHubURL = "https://github.com/"
+
+// ParseGitHubURL returns the owner, the repository, the ref and the path of the URL of a markdown document of a GitHub repository,
+// i.e. https://github.com/<owner>/<repo>/blob/<ref>/<path>.md, the anchor of the URL is ignored.
+func ParseGitHubURL(URL string) (owner, repo, ref, path string, err error) {
+	URL, _, _ = strings.Cut(URL, "#")
+
+	if !strings.HasPrefix(URL, gitHubURL) || !strings.HasSuffix(URL, ".md") {
+		return "", "", "", "", fmt.Errorf("'%s' is not a markdown document of a GitHub repository", URL)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(URL, gitHubURL), "/", 5)
+
+	if len(parts) < 5 || (parts[2] != "blob" && parts[2] != "tree") {
+		return "", "", "", "", fmt.Errorf("'%s' is not a markdown document of a GitHub repository", URL)
 	}
-	return URL, "", ""
+
+	return parts[0], parts[1], parts[3], parts[4], nil
 }
 
 func GetOwnerAndRepoName(str string) (owner, repo string) {
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absoluteLayouts are the absolute time formats accepted by ParseTime.
+var absoluteLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// relativeTimeRegex matches relative times, e.g. +3h, in 2 days or 30 minutes ago.
+var relativeTimeRegex = regexp.MustCompile(`^(?:in\s+)?([+-]?)(\d+)\s*(m|mins?|minutes?|h|hrs?|hours?|d|days?|w|weeks?)(\s+ago)?$`)
+
+// clockTimeRegex matches a time of the day, e.g. 03:00, 3pm or 3:30am.
+var clockTimeRegex = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+
+// ParseTime parses an RFC3339 or a natural language time relative to now, e.g. "saturday 03:00", "tomorrow", "in 3h" or "2 days ago".
+// Times without a timezone are in the location of now.
+func ParseTime(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	input = strings.TrimSpace(strings.TrimSuffix(input, " utc"))
+
+	if input == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	for _, layout := range absoluteLayouts {
+		t, err := time.ParseInLocation(layout, strings.ToUpper(input), now.Location())
+
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	if input == "now" {
+		return now, nil
+	}
+
+	if match := relativeTimeRegex.FindStringSubmatch(input); match != nil {
+		return parseRelativeTime(match, now), nil
+	}
+
+	return parseDayTime(input, now)
+}
+
+// parseRelativeTime returns the time relative to now for a match of relativeTimeRegex.
+func parseRelativeTime(match []string, now time.Time) time.Time {
+	amount, _ := strconv.Atoi(match[2])
+
+	if match[1] == "-" || match[4] != "" {
+		amount = -amount
+	}
+
+	switch match[3][0] {
+	case 'm':
+		return now.Add(time.Duration(amount) * time.Minute)
+	case 'h':
+		return now.Add(time.Duration(amount) * time.Hour)
+	case 'd':
+		return now.AddDate(0, 0, amount)
+	default:
+		return now.AddDate(0, 0, amount*7)
+	}
+}
+
+// parseDayTime parses a day followed by an optional time of the day, or a time of the day of today.
+// Days are today, tomorrow, yesterday or a weekday, which is the next one if prefixed by 'next'.
+func parseDayTime(input string, now time.Time) (time.Time, error) {
+	fields := strings.Fields(input)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayFound := false
+
+	next := len(fields) > 0 && fields[0] == "next"
+
+	if next {
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 {
+		switch fields[0] {
+		case "today":
+			dayFound = true
+		case "tomorrow":
+			day = day.AddDate(0, 0, 1)
+			dayFound = true
+		case "yesterday":
+			day = day.AddDate(0, 0, -1)
+			dayFound = true
+		default:
+			if weekday, ok := parseWeekday(fields[0]); ok {
+				days := (int(weekday) - int(day.Weekday()) + 7) % 7
+
+				if days == 0 && next {
+					days = 7
+				}
+
+				day = day.AddDate(0, 0, days)
+				dayFound = true
+			}
+		}
+	}
+
+	if dayFound {
+		fields = fields[1:]
+	} else if next {
+		return time.Time{}, fmt.Errorf("invalid time '%s'", input)
+	}
+
+	if len(fields) == 0 {
+		if !dayFound {
+			return time.Time{}, fmt.Errorf("invalid time '%s'", input)
+		}
+
+		return day, nil
+	}
+
+	match := clockTimeRegex.FindStringSubmatch(strings.Join(fields, " "))
+
+	if match == nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s', use RFC3339 (e.g. 2021-10-25T03:00:00Z) or natural language (e.g. 'saturday 03:00', 'tomorrow', 'in 3h')", input)
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+
+	if match[3] != "" && (hour < 1 || hour > 12) {
+		return time.Time{}, fmt.Errorf("invalid time of the day '%s'", strings.Join(fields, " "))
+	}
+
+	switch match[3] {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid time of the day '%s'", strings.Join(fields, " "))
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location()), nil
+}
+
+// parseWeekday parses a full or abbreviated weekday name.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+
+		if s == name || (len(s) >= 3 && strings.HasPrefix(name, s)) {
+			return d, true
+		}
+	}
+
+	return time.Sunday, false
+}
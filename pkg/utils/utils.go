@@ -1,14 +1,114 @@
 package utils
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
-// formatTimestamp formats a given timestamp into a UTC format time and returns the string.
+var (
+	// DisplayLocation is the timezone the timestamps are displayed in
+	DisplayLocation = time.UTC
+
+	// RelativeTimes displays the timestamps relative to now, e.g. "in 3h" or "12m ago"
+	RelativeTimes bool
+)
+
+// SetTimeDisplay sets the display timezone, i.e. local, UTC or a named timezone such as Asia/Kolkata, and whether relative times are displayed.
+func SetTimeDisplay(timezone string, relative bool) error {
+	switch strings.ToLower(timezone) {
+	case "", "utc":
+		DisplayLocation = time.UTC
+	case "local":
+		DisplayLocation = time.Local
+	default:
+		location, err := time.LoadLocation(timezone)
+
+		if err != nil {
+			return fmt.Errorf("invalid timezone '%s', use local, UTC or a named timezone e.g. Europe/Prague", timezone)
+		}
+
+		DisplayLocation = location
+	}
+
+	RelativeTimes = relative
+
+	return nil
+}
+
+// ParseTimestamp parses a PagerDuty RFC3339 timestamp, with or without a UTC offset.
+func ParseTimestamp(timestamp string) (time.Time, error) {
+	return time.Parse(time.RFC3339, timestamp)
+}
+
+// FormatTimestamp formats a given PagerDuty timestamp into the display timezone and returns the string.
 func FormatTimestamp(timestamp string) (string, error) {
-	t, err := time.Parse("2006-01-02T15:04:05Z", timestamp)
+	t, err := ParseTimestamp(timestamp)
 
 	if err != nil {
 		return "", err
 	}
 
-	return t.Format("01-02-2006 15:04 UTC"), nil
+	return FormatTime(t), nil
+}
+
+// DisplayTimestamp formats a given PagerDuty timestamp like FormatTimestamp, it is kept as-is if it can't be parsed.
+func DisplayTimestamp(timestamp string) string {
+	formatted, err := FormatTimestamp(timestamp)
+
+	if err != nil {
+		return timestamp
+	}
+
+	return formatted
+}
+
+// FormatTime formats the given time in the display timezone, or relative to now if relative times are enabled.
+func FormatTime(t time.Time) string {
+	if RelativeTimes {
+		return RelativeTime(t, time.Now())
+	}
+
+	return t.In(DisplayLocation).Format("01-02-2006 15:04 MST")
+}
+
+// RelativeTime formats the given time relative to now, e.g. "in 3h" or "12m ago".
+func RelativeTime(t time.Time, now time.Time) string {
+	d := t.Sub(now)
+
+	if d.Round(time.Minute) == 0 {
+		return "now"
+	}
+
+	if d > 0 {
+		return "in " + FormatDuration(d)
+	}
+
+	return FormatDuration(d) + " ago"
+}
+
+// FormatDuration formats a duration into a short human readable string, e.g. 2d5h, 3h12m or 7m.
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	d = d.Round(time.Minute)
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
 }
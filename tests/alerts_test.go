@@ -23,7 +23,10 @@ import (
 	. "github.com/onsi/gomega"
 
 	mockpd "github.com/openshift/pagerduty-short-circuiter/pkg/client/mock"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 )
 
 // incident retuns a pagerduty incident object with pre-configured data.
@@ -84,6 +87,15 @@ var _ = Describe("view alerts", func() {
 		})
 	})
 
+	When("both a single team and an assignment are given", func() {
+		It("exits with the usage exit code", func() {
+			cmd := NewCommand().Args("alerts", "--team", "PABC123", "--assigned-to", "self").Run()
+
+			Expect(cmd.ExitCode()).To(Equal(2))
+			Expect(cmd.ErrString()).To(ContainSubstring("can't be used together"))
+		})
+	})
+
 	When("the alerts command is run", func() {
 		It("returns incidents", func() {
 
@@ -109,6 +121,77 @@ var _ = Describe("view alerts", func() {
 		})
 	})
 
+	When("the team incidents are fetched", func() {
+		AfterEach(func() {
+			err := pdcli.SetSuppression(&config.Config{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("hides the suppressed incidents and does not fail on unassigned incidents", func() {
+			silentTest := pdApi.Incident{
+				Id:          "incident-id-1",
+				Assignments: []pdApi.Assignment{{Assignee: pdApi.APIObject{ID: constants.SilentTest}}},
+			}
+			unassigned := pdApi.Incident{Id: "incident-id-2"}
+			maintenance := pdApi.Incident{Id: "incident-id-3", Title: "Cluster upgrade maintenance"}
+			noisyService := pdApi.Incident{Id: "incident-id-4", Service: pdApi.APIObject{ID: "SERVICE1", Summary: "noisy-service"}}
+
+			incidentsResponse := &pdApi.ListIncidentsResponse{
+				Incidents: []pdApi.Incident{silentTest, unassigned, maintenance, noisyService},
+			}
+
+			mockClient.EXPECT().ListIncidents(gomock.Any()).Return(incidentsResponse, nil).Times(1)
+
+			err := pdcli.SetSuppression(&config.Config{
+				Suppress: &config.Suppression{
+					Users:    []string{constants.SilentTest},
+					Services: []string{"Noisy-Service"},
+					Titles:   []string{"(?i)maintenance"},
+				},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+
+			shown, hidden, err := pdcli.GetAllIncidents(mockClient, &pdApi.ListIncidentsOptions{TeamIDs: []string{"ABCD123"}})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shown).To(Equal([]pdApi.Incident{unassigned}))
+			Expect(hidden).To(Equal([]pdApi.Incident{silentTest, maintenance, noisyService}))
+		})
+
+		It("fetches all the pages of incidents", func() {
+			first := pdApi.Incident{Id: "INCIDENT1"}
+			second := pdApi.Incident{Id: "INCIDENT2"}
+
+			gomock.InOrder(
+				mockClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(opts pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+					Expect(opts.Offset).To(BeZero())
+
+					return &pdApi.ListIncidentsResponse{APIListObject: pdApi.APIListObject{More: true}, Incidents: []pdApi.Incident{first}}, nil
+				}),
+				mockClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(opts pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+					Expect(opts.Offset).To(Equal(opts.Limit))
+
+					return &pdApi.ListIncidentsResponse{Incidents: []pdApi.Incident{second}}, nil
+				}),
+			)
+
+			shown, hidden, err := pdcli.GetAllIncidents(mockClient, &pdApi.ListIncidentsOptions{UserIDs: []string{"my-user-id"}})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shown).To(Equal([]pdApi.Incident{first, second}))
+			Expect(hidden).To(BeEmpty())
+		})
+
+		It("throws an error for an invalid suppressed title", func() {
+			err := pdcli.SetSuppression(&config.Config{
+				Suppress: &config.Suppression{Titles: []string{"("}},
+			})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	When("the alert data is fetched", func() {
 		It("the cluster name is retrieved from the alert service", func() {
 
@@ -158,11 +241,14 @@ var _ = Describe("view alerts", func() {
 					ClusterID:   "cluster-id",
 					ClusterName: "my-cluster-name",
 					Name:        "alert-name",
+					ServiceID:   "my-service-id",
 					Console:     "<nil>",
 					Labels:      "<nil>",
 					Severity:    "high",
 					Status:      "triggered",
 					Sop:         "<nil>",
+					Urgency:     "high",
+					Body:        alertResponse.Alerts[0].Body,
 				},
 			}
 
@@ -205,6 +291,7 @@ var _ = Describe("view alerts", func() {
 				Name:        "alert-name",
 				ClusterID:   "cluster-id",
 				ClusterName: "my-cluster-name",
+				ServiceID:   "my-service-id",
 				Status:      "triggered",
 				Console:     "<nil>",
 				Labels:      "<nil>",
@@ -249,6 +336,7 @@ var _ = Describe("view alerts", func() {
 				Name:        "alert-name",
 				ClusterID:   "cluster-id",
 				ClusterName: "my-cluster-name",
+				ServiceID:   "my-service-id",
 				Status:      "triggered",
 				Console:     "<nil>",
 				Labels:      "<nil>",
@@ -266,6 +354,46 @@ var _ = Describe("view alerts", func() {
 		})
 	})
 
+	When("a 'Missing cluster' alert is parsed", func() {
+		It("keeps the last healthy check-in timestamp and formats it when displayed", func() {
+			var alertData pdcli.Alert
+
+			missingCluster := alert("incident-id-1", "my-service-id", "cluster-has-gone-missing", "", "triggered")
+			missingCluster.Body["details"] = map[string]interface{}{
+				"notes":                 "cluster_id: cluster-id\nrunbook: https://sop.example.com",
+				"name":                  "my-cluster-name.example.com",
+				"last healthy check-in": "2021-10-20T03:00:00Z",
+			}
+
+			err := alertData.ParseAlertData(mockClient, &missingCluster)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(alertData.LastCheckIn).To(Equal("2021-10-20T03:00:00Z"))
+			Expect(pdcli.ParseAlertMetaData(alertData)).To(ContainSubstring("* Last Healthy Check-in: " + utils.DisplayTimestamp("2021-10-20T03:00:00Z") + "\n"))
+
+			column, err := pdcli.LookupColumn("cluster.check_in")
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(column.Value(alertData)).To(Equal(utils.DisplayTimestamp("2021-10-20T03:00:00Z")))
+		})
+
+		It("displays a last healthy check-in which can't be parsed as-is", func() {
+			var alertData pdcli.Alert
+
+			missingCluster := alert("incident-id-1", "my-service-id", "cluster-has-gone-missing", "", "triggered")
+			missingCluster.Body["details"] = map[string]interface{}{
+				"notes":                 "cluster_id: cluster-id\nrunbook: https://sop.example.com",
+				"name":                  "my-cluster-name.example.com",
+				"last healthy check-in": "unknown",
+			}
+
+			err := alertData.ParseAlertData(mockClient, &missingCluster)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pdcli.ParseAlertMetaData(alertData)).To(ContainSubstring("* Last Healthy Check-in: unknown\n"))
+		})
+	})
+
 	When("a user acknowledges an incident(s)", func() {
 		It("it changes the incident status to acknowledged and returns the incident(s)", func() {
 
@@ -314,4 +442,176 @@ var _ = Describe("view alerts", func() {
 
 		})
 	})
+
+	When("the alerts table data is generated", func() {
+		alerts := []pdcli.Alert{
+			{
+				IncidentID:  "incident-id-1",
+				Name:        "alert-name",
+				ClusterName: "my-cluster-name",
+				Sop:         "https://github.com/openshift/ops-sop/blob/master/alert.md",
+				Body: map[string]interface{}{
+					"details": map[string]interface{}{
+						"cluster_id": "cluster-id",
+					},
+				},
+			},
+		}
+
+		It("returns the columns in the given order", func() {
+			headers, data, err := pdcli.GetTableData(alerts, "cluster.name,incident.id,sop")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(headers).To(Equal([]string{"CLUSTER NAME", "INCIDENT ID", "SOP"}))
+			Expect(data).To(Equal([][]string{{"my-cluster-name", "incident-id-1", "yes"}}))
+		})
+
+		It("returns the values of the alert body columns", func() {
+			headers, data, err := pdcli.GetTableData(alerts, "body.details.cluster_id,body.details.missing")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(headers).To(Equal([]string{"DETAILS.CLUSTER_ID", "DETAILS.MISSING"}))
+			Expect(data).To(Equal([][]string{{"cluster-id", "N/A"}}))
+		})
+
+		It("throws an error for an unknown column", func() {
+			_, _, err := pdcli.GetTableData(alerts, "incident.id,unknown")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown column 'unknown'"))
+		})
+	})
+
+	When("the incidents table data is generated", func() {
+		It("uses the incident data", func() {
+			incidents := []pdApi.Incident{
+				{
+					Id:      "incident-id-1",
+					Title:   "incident-title",
+					Urgency: "high",
+					Status:  "triggered",
+					Service: pdApi.APIObject{Summary: "my-service"},
+					Assignments: []pdApi.Assignment{
+						{Assignee: pdApi.APIObject{Summary: "user-a"}},
+						{Assignee: pdApi.APIObject{Summary: "user-b"}},
+					},
+				},
+				{
+					Id:     "incident-id-2",
+					Status: "triggered",
+				},
+			}
+
+			headers, data, err := pdcli.GetTableData(pdcli.IncidentRows(incidents), pdcli.DefaultIncidentColumns)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(headers).To(Equal([]string{"INCIDENT ID", "TITLE", "URGENCY", "INCIDENT STATUS", "SERVICE", "ASSIGNED TO"}))
+			Expect(data).To(Equal([][]string{
+				{"incident-id-1", "incident-title", "high", "triggered", "my-service", "user-a, user-b"},
+				{"incident-id-2", "", "", "triggered", "", ""},
+			}))
+		})
+	})
+
+	When("a saved view is applied", func() {
+		user := &pdApi.User{
+			APIObject: pdApi.APIObject{ID: "my-user-id"},
+			Name:      "my-user",
+		}
+
+		alerts := []pdcli.Alert{
+			{IncidentID: "incident-id-1", Urgency: "low", ClusterName: "cluster-b"},
+			{IncidentID: "incident-id-2", Urgency: "high", ClusterName: "cluster-c"},
+			{IncidentID: "incident-id-3", Urgency: "high", ClusterName: "cluster-a"},
+		}
+
+		It("overrides the statuses and urgencies of the assigned-to option", func() {
+			view := config.View{
+				Name:      "primary",
+				Statuses:  []string{"triggered"},
+				Urgencies: []string{"high"},
+			}
+
+			opts, assignedTo, err := pdcli.ViewOptions(view, "self", user, &config.Config{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(assignedTo).To(Equal("my-user"))
+			Expect(opts.UserIDs).To(Equal([]string{"my-user-id"}))
+			Expect(opts.Statuses).To(Equal([]string{"triggered"}))
+			Expect(opts.Urgencies).To(Equal([]string{"high"}))
+		})
+
+		It("throws an error when the view is assigned to a team and no team is selected", func() {
+			view := config.View{Name: "team", AssignedTo: "team"}
+
+			_, _, err := pdcli.ViewOptions(view, view.AssignedTo, user, &config.Config{})
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("uses the explicit assigned-to option over the view assignment", func() {
+			view := config.View{Name: "team", AssignedTo: "team"}
+			cfg := &config.Config{Teams: []config.Team{{ID: "ABCD123", Name: "my-team"}}}
+
+			opts, assignedTo, err := pdcli.ViewOptions(view, "self", user, cfg)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(assignedTo).To(Equal("my-user"))
+			Expect(opts.UserIDs).To(Equal([]string{"my-user-id"}))
+			Expect(opts.TeamIDs).To(BeEmpty())
+		})
+
+		It("queries the incidents of all the selected teams", func() {
+			cfg := &config.Config{
+				Teams: []config.Team{
+					{ID: "ABCD123", Name: "my-team-a"},
+					{ID: "EFGH456", Name: "my-team-b"},
+				},
+			}
+
+			opts, assignedTo, err := pdcli.AssignmentOptions("team", user, cfg)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(assignedTo).To(Equal("my-team-a, my-team-b"))
+			Expect(opts.TeamIDs).To(Equal([]string{"ABCD123", "EFGH456"}))
+		})
+
+		It("filters and sorts the alerts", func() {
+			filtered, err := pdcli.FilterAlerts(alerts, []string{"urgency=high", "cluster.name~^cluster-[ac]$"})
+
+			Expect(err).ToNot(HaveOccurred())
+
+			err = pdcli.SortAlerts(filtered, "-cluster.name")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filtered).To(Equal([]pdcli.Alert{alerts[1], alerts[2]}))
+		})
+
+		It("splits a filter on its first operator", func() {
+			filter, err := pdcli.ParseFilter("alert=foo~bar")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filter.Column.Key).To(Equal("alert"))
+			Expect(filter.Operator).To(Equal("="))
+			Expect(filter.Value).To(Equal("foo~bar"))
+
+			filter, err = pdcli.ParseFilter("alert~^foo=bar!=baz$")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filter.Operator).To(Equal("~"))
+			Expect(filter.Value).To(Equal("^foo=bar!=baz$"))
+
+			filter, err = pdcli.ParseFilter("urgency!=high")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filter.Operator).To(Equal("!="))
+			Expect(filter.Value).To(Equal("high"))
+		})
+
+		It("throws an error for an invalid filter", func() {
+			_, err := pdcli.FilterAlerts(alerts, []string{"urgency"})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
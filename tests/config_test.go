@@ -0,0 +1,337 @@
+package tests
+
+import (
+	"bytes"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kiteConfig "github.com/openshift/pagerduty-short-circuiter/cmd/kite/config"
+	mockpd "github.com/openshift/pagerduty-short-circuiter/pkg/client/mock"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+// schedule retuns a pagerduty schedule reference.
+func schedule(id string, name string) pdApi.APIObject {
+	return pdApi.APIObject{ID: id, Type: "schedule_reference", Summary: name}
+}
+
+var _ = Describe("kite config init", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockClient *mockpd.MockPagerDutyClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = mockpd.NewMockPagerDutyClient(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	When("the org profile is discovered", func() {
+		teamPolicies := &pdApi.ListEscalationPoliciesResponse{
+			EscalationPolicies: []pdApi.EscalationPolicy{
+				{
+					APIObject: pdApi.APIObject{ID: "POLICY1"},
+					EscalationRules: []pdApi.EscalationRule{
+						{Targets: []pdApi.APIObject{schedule("SCHED01", "Weekday Primary"), {ID: "USER001", Type: "user_reference"}}},
+						{Targets: []pdApi.APIObject{schedule("SCHED02", "Weekday Secondary")}},
+						{Targets: []pdApi.APIObject{schedule("SCHED03", "Management")}},
+					},
+				},
+				{
+					APIObject: pdApi.APIObject{ID: "POLICY2"},
+					EscalationRules: []pdApi.EscalationRule{
+						{Targets: []pdApi.APIObject{schedule("SCHED01", "Weekday Primary"), schedule("SCHED04", "Weekend")}},
+					},
+				},
+			},
+		}
+
+		silentPolicies := &pdApi.ListEscalationPoliciesResponse{
+			EscalationPolicies: []pdApi.EscalationPolicy{
+				{APIObject: pdApi.APIObject{ID: "SILENT1"}, Name: "Silent Test"},
+				{APIObject: pdApi.APIObject{ID: "SILENT2"}, Name: "CAD Silent Test"},
+			},
+		}
+
+		It("suggests the schedules named after the on-call roles", func() {
+			mockClient.EXPECT().ListEscalationPolicies(pdApi.ListEscalationPoliciesOptions{
+				APIListObject: pdApi.APIListObject{Limit: 100},
+				TeamIDs:       []string{"ABCD123"},
+			}).Return(teamPolicies, nil).Times(1)
+
+			mockClient.EXPECT().ListEscalationPolicies(pdApi.ListEscalationPoliciesOptions{
+				APIListObject: pdApi.APIListObject{Limit: 100},
+				Query:         "silent",
+			}).Return(silentPolicies, nil).Times(1)
+
+			var stdin bytes.Buffer
+
+			// Accept the suggestions, select the manager schedule as investigator and a single silent test policy
+			stdin.Write([]byte("\n\n\n\n3\n2\n"))
+
+			org, err := kiteConfig.DiscoverOrg(mockClient, "ABCD123", config.DefaultOrg(), &stdin)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(org.TeamID).To(Equal("ABCD123"))
+			Expect(org.ScheduleIDs()).To(Equal([]string{"SCHED01", "SCHED02", "SCHED03", "SCHED04", "SCHED03"}))
+			Expect(org.SilentTestEscalationPolicyIDs).To(Equal([]string{"SILENT2"}))
+			Expect(org.SilentTestUserID).To(Equal(config.DefaultOrg().SilentTestUserID))
+		})
+
+		It("throws an error for an invalid option", func() {
+			mockClient.EXPECT().ListEscalationPolicies(gomock.Any()).Return(teamPolicies, nil).Times(1)
+
+			var stdin bytes.Buffer
+
+			stdin.Write([]byte("9\n"))
+
+			_, err := kiteConfig.DiscoverOrg(mockClient, "ABCD123", config.DefaultOrg(), &stdin)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the org profile is partially configured", func() {
+		It("uses the defaults for the missing IDs", func() {
+			cfg := &config.Config{Org: &config.Org{PrimaryScheduleID: "SCHED01"}}
+
+			org := cfg.Organization()
+
+			Expect(org.PrimaryScheduleID).To(Equal("SCHED01"))
+			Expect(org.SecondaryScheduleID).To(Equal(config.DefaultOrg().SecondaryScheduleID))
+			Expect(cfg.Suppression()).To(Equal(config.DefaultSuppression()))
+		})
+	})
+})
+
+var _ = Describe("kite config view", func() {
+	configFile := `{
+  "api_key": "y_NbAkKc66ryYTWUXYEu",
+  "gh_token": "ghp_token",
+  "timezone": "Europe/Prague",
+  "teams": [{"id": "PABC123", "name": "Platform SRE"}],
+  "org": {"primary_schedule_id": "PPRIMRY"}
+}`
+
+	It("prints the origin of each value, flags before the environment before the file", func() {
+		result := NewCommand().
+			Config(configFile).
+			Env("KITE_TIMEZONE", "Asia/Kolkata").
+			Env("KITE_HISTORY_DAYS", "7").
+			Args("config", "view", "--show-origin", "--history-days", "30", "--relative-times").
+			Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+
+		out := result.OutString()
+
+		Expect(out).To(MatchRegexp(`timezone\s+Asia/Kolkata\s+env KITE_TIMEZONE`))
+		Expect(out).To(MatchRegexp(`history_days\s+30\s+flag --history-days`))
+		Expect(out).To(MatchRegexp(`relative_times\s+true\s+flag --relative-times`))
+		Expect(out).To(MatchRegexp(`teams\s+PABC123\s+file `))
+		Expect(out).To(MatchRegexp(`org.primary_schedule_id\s+PPRIMRY\s+file `))
+		Expect(out).To(MatchRegexp(`org.secondary_schedule_id\s+\S+\s+default`))
+	})
+
+	It("redacts the secrets", func() {
+		result := NewCommand().Config(configFile).Args("config", "view").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.OutString()).To(MatchRegexp(`api_key\s+<redacted>`))
+		Expect(result.OutString()).ToNot(ContainSubstring("y_NbAkKc66ryYTWUXYEu"))
+		Expect(result.OutString()).ToNot(ContainSubstring("ghp_token"))
+	})
+
+	It("reads the configuration from the environment when there is no configuration file", func() {
+		result := NewCommand().
+			Env("KITE_PD_API_KEY", "y_NbAkKc66ryYTWUXYEu").
+			Env("KITE_TEAM_ID", "PABC123,PDEF456").
+			Args("config", "view", "--show-origin").
+			Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.OutString()).To(MatchRegexp(`api_key\s+<redacted>\s+env KITE_PD_API_KEY`))
+		Expect(result.OutString()).To(MatchRegexp(`teams\s+PABC123,PDEF456\s+env KITE_TEAM_ID`))
+		Expect(result.ConfigFile()).To(BeEmpty())
+	})
+
+	It("doesn't accept the secrets as flags", func() {
+		result := NewCommand().Args("config", "view", "--pd-api-key", "y_NbAkKc66ryYTWUXYEu").Run()
+
+		Expect(result.ExitCode()).To(Equal(2))
+	})
+
+	It("fails on an invalid override", func() {
+		result := NewCommand().Config(configFile).Env("KITE_HISTORY_DAYS", "two weeks").Args("config", "view").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("KITE_HISTORY_DAYS"))
+	})
+})
+
+var _ = Describe("kite config set", func() {
+	configFile := `{
+  "version": 1,
+  "api_key": "y_NbAkKc66ryYTWUXYEu",
+  "teams": [{"id": "PABC123"}]
+}`
+
+	It("saves and prints a valid value", func() {
+		result := NewCommand().Config(configFile).Args("config", "set", "timezone", "Europe/Prague").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).To(ContainSubstring(`"timezone": "Europe/Prague"`))
+
+		result = NewCommand().Config(result.ConfigString()).Args("config", "get", "timezone").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.OutString()).To(Equal("Europe/Prague\n"))
+	})
+
+	It("sets the column presets and the keybindings", func() {
+		result := NewCommand().Config(configFile).Args("config", "set", "column_presets.triage", "incident.id,cluster.name,status").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+
+		result = NewCommand().Config(result.ConfigString()).Args("config", "set", "keybindings.refresh", "R").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).To(MatchJSON(`{
+  "version": 1,
+  "api_key": "y_NbAkKc66ryYTWUXYEu",
+  "teams": [{"id": "PABC123", "name": ""}],
+  "column_presets": {"triage": "incident.id,cluster.name,status"},
+  "keybindings": {"refresh": "R"}
+}`))
+	})
+
+	It("removes an unset key", func() {
+		result := NewCommand().Config(configFile).Args("config", "set", "refresh_interval", "1m").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).To(ContainSubstring(`"refresh_interval": "1m"`))
+
+		result = NewCommand().Config(result.ConfigString()).Args("config", "unset", "refresh_interval").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).ToNot(ContainSubstring("refresh_interval"))
+	})
+
+	invalidValues := []struct {
+		name, key, value, message string
+	}{
+		{"timezone", "timezone", "Mars/Olympus", "invalid timezone"},
+		{"refresh interval too short", "refresh_interval", "2s", "at least 10s"},
+		{"refresh interval", "refresh_interval", "often", "not a duration"},
+		{"keybinding action", "keybindings.fly", "f", "unknown action"},
+		{"keybinding key", "keybindings.refresh", "ctrl+r", "not a single character"},
+		{"keybinding colliding within a page", "keybindings.refresh", "h", "toggle_suppressed"},
+		{"column preset", "column_presets.triage", "incident.id,colour", "colour"},
+		{"unknown key", "colour", "blue", "colour"},
+	}
+
+	for _, invalid := range invalidValues {
+		invalid := invalid
+
+		It("rejects an invalid "+invalid.name, func() {
+			result := NewCommand().Config(configFile).Args("config", "set", invalid.key, invalid.value).Run()
+
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(invalid.message))
+			Expect(result.ConfigString()).To(Equal(configFile))
+		})
+	}
+
+	It("refuses to set a secret", func() {
+		result := NewCommand().Config(configFile).Args("config", "set", "api_key", "y_NbAkKc66ryYTWUXYEu").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("kite login"))
+	})
+})
+
+var _ = Describe("kite config edit", func() {
+	configFile := `{
+  "version": 1,
+  "api_key": "y_NbAkKc66ryYTWUXYEu",
+  "timezone": "Europe/Prague"
+}`
+
+	It("saves the edited configuration", func() {
+		result := NewCommand().
+			Config(configFile).
+			Env("VISUAL", "").
+			Env("EDITOR", `sed -i s|Europe/Prague|Asia/Kolkata|`).
+			Args("config", "edit").
+			Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).To(ContainSubstring(`"timezone": "Asia/Kolkata"`))
+	})
+
+	It("keeps the configuration file when the edited configuration is invalid", func() {
+		result := NewCommand().
+			Config(configFile).
+			Env("VISUAL", "").
+			Env("EDITOR", `sed -i s|Europe/Prague|Mars/Olympus|`).
+			Args("config", "edit").
+			Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("invalid timezone"))
+		Expect(result.ErrString()).To(ContainSubstring("kept in"))
+		Expect(result.ConfigString()).To(Equal(configFile))
+	})
+
+	It("rejects the keys bound to two actions of the same page", func() {
+		result := NewCommand().
+			Config(configFile).
+			Env("VISUAL", "").
+			Env("EDITOR", `sed -i s|"timezone"|"keybindings":{"next_day":"["},"timezone"|`).
+			Args("config", "edit").
+			Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("previous_day"))
+		Expect(result.ConfigString()).To(Equal(configFile))
+	})
+})
+
+var _ = Describe("configuration migrations", func() {
+	It("migrates a legacy configuration file to the current version", func() {
+		result := NewCommand().
+			Config(`{"api_key": "y_NbAkKc66ryYTWUXYEu", "team_id": "PABC123"}`).
+			Args("config", "view").
+			Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).To(MatchJSON(`{
+  "version": 1,
+  "api_key": "y_NbAkKc66ryYTWUXYEu",
+  "teams": [{"id": "PABC123", "name": ""}]
+}`))
+	})
+
+	It("refuses a configuration file of a newer version", func() {
+		result := NewCommand().Config(`{"version": 99, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).Args("config", "view").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("version"))
+	})
+
+	It("reports why the configuration file can't be loaded", func() {
+		for _, args := range [][]string{{"alerts"}, {"config", "init"}, {"teams"}} {
+			result := NewCommand().Config(`{"version": 99, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).Args(args...).Run()
+
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("version"), args[0])
+			Expect(result.ErrString()).ToNot(ContainSubstring("configuration file not found"), args[0])
+		}
+	})
+})
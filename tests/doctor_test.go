@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/doctor"
+)
+
+var _ = Describe("kite doctor", func() {
+	Describe("config file check", func() {
+		var dir string
+		var path string
+
+		BeforeEach(func() {
+			var err error
+
+			dir, err = os.MkdirTemp("", "kite-doctor-*.d")
+			Expect(err).ToNot(HaveOccurred())
+
+			path = filepath.Join(dir, "config.json")
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("passes when only the user can read the config file", func() {
+			Expect(os.WriteFile(path, []byte("{}"), 0600)).To(Succeed())
+
+			Expect(doctor.ConfigFile(path).Status).To(Equal(doctor.Pass))
+		})
+
+		It("warns when other users can read the config file", func() {
+			Expect(os.WriteFile(path, []byte("{}"), 0600)).To(Succeed())
+			Expect(os.Chmod(path, 0644)).To(Succeed())
+
+			check := doctor.ConfigFile(path)
+
+			Expect(check.Status).To(Equal(doctor.Warn))
+			Expect(check.Message).To(ContainSubstring("0644"))
+		})
+
+		It("fails when the config file doesn't exist", func() {
+			Expect(doctor.ConfigFile(path).Status).To(Equal(doctor.Fail))
+		})
+	})
+
+	Describe("rate limit check", func() {
+		rateLimit := func(limit string, remaining string) http.Header {
+			header := http.Header{}
+			header.Set(doctor.RateLimitHeader, limit)
+			header.Set(doctor.RateLimitRemainingHeader, remaining)
+
+			return header
+		}
+
+		It("passes with enough requests remaining", func() {
+			check := doctor.RateLimit(rateLimit("960", "900"))
+
+			Expect(check.Status).To(Equal(doctor.Pass))
+			Expect(check.Message).To(Equal("900/960 requests remaining"))
+		})
+
+		It("warns when few requests remain", func() {
+			Expect(doctor.RateLimit(rateLimit("960", "100")).Status).To(Equal(doctor.Warn))
+		})
+
+		It("fails when no requests remain", func() {
+			Expect(doctor.RateLimit(rateLimit("960", "0")).Status).To(Equal(doctor.Fail))
+		})
+
+		It("warns when the rate limit is not reported", func() {
+			Expect(doctor.RateLimit(http.Header{}).Status).To(Equal(doctor.Warn))
+		})
+	})
+
+	Describe("terminal check", func() {
+		It("warns when no terminal emulator is selected", func() {
+			Expect(doctor.Terminal(&config.Config{}).Status).To(Equal(doctor.Warn))
+		})
+
+		It("fails when the terminal emulator is not installed", func() {
+			Expect(doctor.Terminal(&config.Config{Terminal: "kite-missing-terminal"}).Status).To(Equal(doctor.Fail))
+		})
+	})
+
+	It("reports the failed checks and exits with an error", func() {
+		result := NewCommand().Config(`{"api_key": "invalid"}`).Args("doctor").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.OutString()).To(MatchRegexp(`config file\s+PASS`))
+		Expect(result.OutString()).To(MatchRegexp(`pagerduty api key\s+FAIL\s+the API key format is invalid`))
+		Expect(result.OutString()).To(MatchRegexp(`github token\s+WARN\s+no GitHub token`))
+		Expect(result.ErrString()).To(ContainSubstring("some checks failed"))
+	})
+})
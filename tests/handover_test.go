@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"encoding/json"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	mockpd "github.com/openshift/pagerduty-short-circuiter/pkg/client/mock"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	handover "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/handover"
+)
+
+var _ = Describe("kite handover", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockClient *mockpd.MockPagerDutyClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = mockpd.NewMockPagerDutyClient(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	since := time.Date(2021, 10, 25, 3, 30, 0, 0, time.UTC)
+	until := since.Add(time.Hour * 5)
+
+	cfg := &config.Config{
+		Teams: []config.Team{{ID: "TEAM1", Name: "Platform SRE"}},
+		Org:   &config.Org{PrimaryScheduleID: "PRIMARY"},
+	}
+
+	user := &pdApi.User{APIObject: pdApi.APIObject{ID: "USER001"}, Name: "Red Hat SRE"}
+
+	When("the handover report is generated", func() {
+		It("compiles the open incidents and the incoming on-call of the team", func() {
+			acknowledged := pdApi.Incident{Id: "INCIDENT1", Title: "ClusterDown", Status: "acknowledged", Urgency: "high"}
+			triggered := pdApi.Incident{Id: "INCIDENT2", Title: "ClusterOperatorDegraded", Status: "triggered", Urgency: "high"}
+
+			mockClient.EXPECT().ListIncidents(gomock.Any()).Return(&pdApi.ListIncidentsResponse{
+				Incidents: []pdApi.Incident{acknowledged, triggered},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentAlerts("INCIDENT1").Return(&pdApi.ListAlertsResponse{
+				Alerts: []pdApi.IncidentAlert{alert("INCIDENT1", "my-service-id", "ClusterDown", "cluster-id", "triggered")},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentAlerts("INCIDENT2").Return(&pdApi.ListAlertsResponse{}, nil).Times(1)
+
+			mockClient.EXPECT().GetService("my-service-id", gomock.Any()).Return(&pdApi.Service{Description: "my-cluster-name"}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentNotes("INCIDENT1").Return([]pdApi.IncidentNote{
+				{Content: "first", CreatedAt: "2021-10-25T04:00:00Z"},
+				{Content: "second", CreatedAt: "2021-10-25T05:00:00Z"},
+				{Content: "third", CreatedAt: "2021-10-25T06:00:00Z"},
+				{Content: "latest", CreatedAt: "2021-10-25T07:00:00Z"},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentNotes("INCIDENT2").Return(nil, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentLogEntries("INCIDENT1", gomock.Any()).Return(&pdApi.ListIncidentLogEntriesResponse{
+				LogEntries: []pdApi.LogEntry{
+					{CommonLogEntryField: pdApi.CommonLogEntryField{APIObject: pdApi.APIObject{Summary: "Acknowledged by Red Hat SRE"}, CreatedAt: "2021-10-25T04:10:00Z"}},
+					{CommonLogEntryField: pdApi.CommonLogEntryField{APIObject: pdApi.APIObject{Summary: "Triggered through the API"}, CreatedAt: "2021-10-25T04:00:00Z"}},
+				},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentLogEntries("INCIDENT2", gomock.Any()).Return(&pdApi.ListIncidentLogEntriesResponse{}, nil).Times(1)
+
+			mockClient.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(&pdApi.Schedule{}, nil).AnyTimes()
+
+			mockClient.EXPECT().ListOnCalls(gomock.Any()).Return(&pdApi.ListOnCallsResponse{
+				OnCalls: []pdApi.OnCall{
+					{
+						User:     pdApi.User{Summary: "Incoming SRE"},
+						Schedule: pdApi.Schedule{APIObject: pdApi.APIObject{Summary: "0-SREP Weekday Primary"}},
+						Start:    "2021-10-25T08:30:00Z",
+						End:      "2021-10-25T13:30:00Z",
+					},
+				},
+			}, nil).Times(1)
+
+			report, err := handover.GenerateReport(mockClient, cfg, user, since, until, until)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Team).To(Equal("Platform SRE"))
+			Expect(report.Incidents).To(HaveLen(2))
+
+			// Triggered incidents are pending and listed first
+			Expect(report.Incidents[0].ID).To(Equal("INCIDENT2"))
+			Expect(report.Incidents[1].Alerts).To(HaveLen(1))
+			Expect(report.Incidents[1].Alerts[0]).To(ContainSubstring("* Cluster ID: cluster-id"))
+			Expect(report.Incidents[1].Notes).To(HaveLen(handover.LatestNotes))
+			Expect(report.Incidents[1].Notes[0].Content).To(Equal("latest"))
+			Expect(report.Incidents[1].Timeline[0].Summary).To(Equal("Triggered through the API"))
+			Expect(report.Incoming).To(Equal([]handover.Oncall{
+				{Name: "Incoming SRE", Role: "0-SREP Weekday Primary", Start: "2021-10-25T08:30:00Z", End: "2021-10-25T13:30:00Z"},
+			}))
+		})
+
+		It("pages through all the open incidents of the team", func() {
+			first := mockClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(opts pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+				Expect(opts.Offset).To(BeZero())
+
+				return &pdApi.ListIncidentsResponse{
+					APIListObject: pdApi.APIListObject{More: true},
+					Incidents:     []pdApi.Incident{{Id: "INCIDENT1", Status: "acknowledged"}},
+				}, nil
+			})
+
+			mockClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(opts pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+				Expect(opts.Offset).To(Equal(opts.Limit))
+
+				return &pdApi.ListIncidentsResponse{
+					Incidents: []pdApi.Incident{{Id: "INCIDENT2", Status: "triggered"}},
+				}, nil
+			}).After(first)
+
+			mockClient.EXPECT().ListIncidentAlerts(gomock.Any()).Return(&pdApi.ListAlertsResponse{}, nil).Times(2)
+			mockClient.EXPECT().ListIncidentNotes(gomock.Any()).Return(nil, nil).Times(2)
+			mockClient.EXPECT().ListIncidentLogEntries(gomock.Any(), gomock.Any()).Return(&pdApi.ListIncidentLogEntriesResponse{}, nil).Times(2)
+			mockClient.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(&pdApi.Schedule{}, nil).AnyTimes()
+			mockClient.EXPECT().ListOnCalls(gomock.Any()).Return(&pdApi.ListOnCallsResponse{}, nil).Times(1)
+
+			report, err := handover.GenerateReport(mockClient, cfg, user, since, until, until)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Incidents).To(HaveLen(2))
+		})
+	})
+
+	When("the handover report is rendered", func() {
+		report := &handover.Report{
+			Team:        "Platform SRE",
+			ShiftStart:  since,
+			ShiftEnd:    until,
+			GeneratedAt: until,
+			Incidents: []handover.Incident{
+				{ID: "INCIDENT1", Title: "ClusterDown", Status: "acknowledged", Notes: []handover.Note{{Author: "Red Hat SRE", Content: "Waiting on the customer"}}},
+				{ID: "INCIDENT2", Title: "ClusterOperatorDegraded", Status: "triggered"},
+			},
+			Incoming: []handover.Oncall{{Name: "Incoming SRE", Role: "0-SREP Weekday Primary"}},
+		}
+
+		It("renders the report as Markdown", func() {
+			rendered, err := handover.Render(report, handover.MarkdownFormat)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rendered).To(HavePrefix("# Shift Handover - Platform SRE\n"))
+			Expect(rendered).To(ContainSubstring("* **Incoming SRE** (0-SREP Weekday Primary)"))
+			Expect(rendered).To(ContainSubstring("## Pending - Triggered Incidents (1)\n\n### [INCIDENT2] ClusterOperatorDegraded\n"))
+			Expect(rendered).To(ContainSubstring("## In Progress - Acknowledged Incidents (1)\n\n### [INCIDENT1] ClusterDown\n"))
+			Expect(rendered).To(ContainSubstring("**Red Hat SRE**: Waiting on the customer\n"))
+		})
+
+		It("renders the report as JSON", func() {
+			rendered, err := handover.Render(report, handover.JSONFormat)
+
+			Expect(err).ToNot(HaveOccurred())
+
+			var decoded handover.Report
+			Expect(json.Unmarshal([]byte(rendered), &decoded)).To(Succeed())
+			Expect(decoded.Incidents).To(HaveLen(2))
+			Expect(decoded.Incoming[0].Name).To(Equal("Incoming SRE"))
+		})
+
+		It("throws an error for an invalid format", func() {
+			_, err := handover.Render(report, "html")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kiteIncident "github.com/openshift/pagerduty-short-circuiter/cmd/kite/incident"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	mockpd "github.com/openshift/pagerduty-short-circuiter/pkg/client/mock"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	oncall "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+)
+
+var _ = Describe("kite incident responders", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockClient *mockpd.MockPagerDutyClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = mockpd.NewMockPagerDutyClient(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	now := time.Date(2021, 10, 25, 10, 0, 0, 0, time.UTC)
+
+	layers := []oncall.OncallLayer{
+		{
+			Start: now.Add(-time.Hour),
+			End:   now.Add(time.Hour * 4),
+			Users: []oncall.OncallUser{
+				{Name: "Primary SRE", UserID: "USER001", OncallRole: "0-SREP Weekday Primary", EscalationPolicy: "SRE Escalation", EscalationPolicyID: "POLICY1"},
+				{Name: "Secondary SRE", UserID: "USER002", OncallRole: "0-SREP Weekday Secondary", EscalationPolicy: "SRE Escalation", EscalationPolicyID: "POLICY1"},
+			},
+		},
+		{
+			Start: now.Add(time.Hour * 4),
+			End:   now.Add(time.Hour * 9),
+			Users: []oncall.OncallUser{
+				{Name: "Next SRE", UserID: "USER003", OncallRole: "0-SREP Weekday Primary"},
+			},
+		},
+	}
+
+	allTeams := []oncall.OncallUser{
+		{Name: "Other SRE", UserID: "USER004", EscalationPolicy: "Another Team Escalation", EscalationPolicyID: "POLICY2"},
+		{Name: "Other Manager", UserID: "USER005", EscalationPolicy: "Another Team Escalation", EscalationPolicyID: "POLICY2"},
+	}
+
+	When("the responder targets are listed", func() {
+		It("lists the users on-call and the escalation policies", func() {
+			targets := oncall.ResponderTargets(layers, allTeams, now)
+
+			Expect(targets).To(Equal([]oncall.ResponderTarget{
+				{ID: "USER001", Type: oncall.UserTarget, Name: "Primary SRE", Role: "0-SREP Weekday Primary"},
+				{ID: "USER002", Type: oncall.UserTarget, Name: "Secondary SRE", Role: "0-SREP Weekday Secondary"},
+				{ID: "POLICY2", Type: oncall.EscalationPolicyTarget, Name: "Another Team Escalation", Role: "Escalation Policy"},
+				{ID: "POLICY1", Type: oncall.EscalationPolicyTarget, Name: "SRE Escalation", Role: "Escalation Policy"},
+			}))
+		})
+
+		It("prompts the user to select the responders", func() {
+			var stdin bytes.Buffer
+			stdin.Write([]byte("1,3\n"))
+
+			targets := oncall.ResponderTargets(layers, allTeams, now)
+			selected, err := kiteIncident.SelectResponderTargets(targets, &stdin)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(Equal([]oncall.ResponderTarget{targets[0], targets[2]}))
+		})
+
+		It("finds a target by name", func() {
+			targets := oncall.ResponderTargets(layers, allTeams, now)
+
+			target, err := oncall.FindResponderTarget(targets, "another team escalation")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.ID).To(Equal("POLICY2"))
+
+			_, err = oncall.FindResponderTarget(targets, "Next SRE")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("responders are requested", func() {
+		It("requests the targets on behalf of the current user", func() {
+			mockClient.EXPECT().GetCurrentUser(gomock.Any()).Return(&pdApi.User{APIObject: pdApi.APIObject{ID: "USER001"}, Email: "sre@redhat.com"}, nil).Times(1)
+
+			var opts client.ResponderRequestOptions
+
+			mockClient.EXPECT().ResponderRequest("INCIDENT1", gomock.Any()).DoAndReturn(func(incidentID string, o client.ResponderRequestOptions) (*pdApi.ResponderRequestResponse, error) {
+				opts = o
+
+				return &pdApi.ResponderRequestResponse{}, nil
+			}).Times(1)
+
+			target := oncall.ResponderTarget{ID: "POLICY2", Type: oncall.EscalationPolicyTarget}
+			_, err := pdcli.RequestResponders(mockClient, "INCIDENT1", pdcli.DefaultResponderMessage, []pdApi.APIObject{target.Reference()})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opts.From).To(Equal("sre@redhat.com"))
+
+			// The targets are wrapped in responder_request_target objects
+			body, err := json.Marshal(opts)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(body).To(MatchJSON(`{
+				"message": "` + pdcli.DefaultResponderMessage + `",
+				"requester_id": "USER001",
+				"responder_request_targets": [{"responder_request_target": {"id": "POLICY2", "type": "escalation_policy_reference"}}]
+			}`))
+		})
+
+		It("throws an error if no responder is selected", func() {
+			_, err := pdcli.RequestResponders(mockClient, "INCIDENT1", pdcli.DefaultResponderMessage, nil)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
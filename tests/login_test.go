@@ -1,5 +1,98 @@
 package tests
 
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+var _ = Describe("kite login non-interactive", func() {
+	It("fails when the API key is missing and can't be prompted for", func() {
+		result := NewCommand().Args("login", "--no-github").Run()
+
+		Expect(result.ExitCode()).To(Equal(4))
+		Expect(result.ErrString()).To(ContainSubstring("--api-key-stdin"))
+		Expect(result.ConfigFile()).To(BeEmpty())
+	})
+
+	It("fails when the GitHub token is missing and can't be prompted for", func() {
+		result := NewCommand().GetStdIn(constants.SampleKey+"\n").Args("login", "--api-key-stdin", "--team", "PABC123").Run()
+
+		Expect(result.ExitCode()).To(Equal(4))
+		Expect(result.ErrString()).To(ContainSubstring("--no-github"))
+		Expect(result.ConfigFile()).To(BeEmpty())
+	})
+
+	It("fails when no team is selected and can't be prompted for", func() {
+		result := NewCommand().GetStdIn(constants.SampleKey+"\n").Args("login", "--api-key-stdin", "--no-github").Run()
+
+		Expect(result.ExitCode()).To(Equal(4))
+		Expect(result.ErrString()).To(ContainSubstring("--team"))
+		Expect(result.ConfigFile()).To(BeEmpty())
+	})
+
+	It("rejects an invalid API key read from the standard input", func() {
+		result := NewCommand().GetStdIn("ABCDEFGHIJ12345\n").Args("login", "--api-key-stdin", "--no-github", "--team", "PABC123").Run()
+
+		Expect(result.ExitCode()).To(Equal(2))
+		Expect(result.ConfigFile()).To(BeEmpty())
+	})
+
+	It("keeps the settings of the configuration file when replacing an invalid API key", func() {
+		result := NewCommand().
+			Config(`{"version": 1, "api_key": "invalid", "timezone": "UTC"}`).
+			GetStdIn(constants.SampleKey+"\n").
+			Args("login", "--api-key-stdin", "--no-github", "--team", "PABC123").
+			Run()
+
+		Expect(result.ConfigString()).To(ContainSubstring(constants.SampleKey))
+		Expect(result.ConfigString()).To(MatchRegexp(`"timezone":\s*"UTC"`))
+	})
+
+	It("doesn't overwrite the configuration file when it can't be read", func() {
+		configuration := `{"version": 99, "api_key": "` + constants.SampleKey + `", "timezone": "UTC"}`
+		result := NewCommand().Config(configuration).GetStdIn(constants.SampleKey+"\n").Args("login", "--api-key-stdin", "--no-github", "--team", "PABC123").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ConfigString()).To(MatchJSON(configuration))
+	})
+
+	It("rejects conflicting flags", func() {
+		result := NewCommand().Args("login", "--api-key", constants.SampleKey, "--api-key-stdin").Run()
+
+		Expect(result.ExitCode()).To(Equal(2))
+		Expect(result.ErrString()).To(ContainSubstring("can't be used together"))
+	})
+
+	It("rejects a GitHub token when logging in without GitHub", func() {
+		result := NewCommand().Args("login", "--access-token", "ghp_token", "--no-github").Run()
+
+		Expect(result.ExitCode()).To(Equal(2))
+	})
+
+	It("fails on a missing GitHub token file", func() {
+		dir, err := os.MkdirTemp("", "kite-login-*")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		result := NewCommand().
+			Args("login", "--api-key", constants.SampleKey, "--gh-token-file", filepath.Join(dir, "token"), "--team", "PABC123").
+			Run()
+
+		Expect(result.ExitCode()).To(Equal(2))
+		Expect(result.ErrString()).To(ContainSubstring("GitHub token file"))
+	})
+
+	It("exits with the usage exit code on an unknown flag", func() {
+		result := NewCommand().Args("login", "--api-token", constants.SampleKey).Run()
+
+		Expect(result.ExitCode()).To(Equal(2))
+	})
+})
+
 // TODO : Update Login Test Cases
 // import (
 // 	pdApi "github.com/PagerDuty/go-pagerduty"
@@ -73,6 +73,12 @@ func (tc *TestCommand) Env(name, value string) *TestCommand {
 	return tc
 }
 
+// Config sets the content of the configuration file of the CLI command.
+func (tc *TestCommand) Config(value string) *TestCommand {
+	tc.config = value
+	return tc
+}
+
 // Args adds a set of arguments to the command.
 func (tc *TestCommand) Args(values ...string) *TestCommand {
 	tc.args = append(tc.args, values...)
@@ -90,6 +96,11 @@ func (tr *TestResult) ConfigString() string {
 	return string(tr.configData)
 }
 
+// OutString returns the standard output of the test command.
+func (tr *TestResult) OutString() string {
+	return string(tr.out)
+}
+
 // Err returns the standard errour output of the test command.
 func (tr *TestResult) ErrString() string {
 	return string(tr.err)
@@ -1,12 +1,18 @@
 package tests
 
 import (
+	"regexp"
+	"strings"
+	"time"
+
 	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	mockpd "github.com/openshift/pagerduty-short-circuiter/pkg/client/mock"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 )
 
 var _ = Describe("kite oncall", func() {
@@ -36,11 +42,46 @@ var _ = Describe("kite oncall", func() {
 	When("kite oncall is run", func() {
 		It("shows users currently oncall", func() {
 
+			org := config.Org{
+				PrimaryScheduleID:   "PRIMARY",
+				SecondaryScheduleID: "SECONDARY",
+				LayerNames:          map[string]string{"LAYER02": "Layer 2 [ APAC-W ]"},
+			}
+
+			since := time.Date(2021, 10, 25, 0, 0, 0, 0, time.UTC)
+			until := since.Add(time.Hour * 24)
+
+			scheduleResponse := &pdApi.Schedule{
+				ScheduleLayers: []pdApi.ScheduleLayer{
+					{
+						APIObject: pdApi.APIObject{ID: "LAYER03"},
+						Name:      "Layer 3",
+						RenderedScheduleEntries: []pdApi.RenderedScheduleEntry{
+							{Start: "2021-10-25T08:30:00Z", End: "2021-10-25T13:30:00Z"},
+						},
+					},
+					{
+						APIObject: pdApi.APIObject{ID: "LAYER02"},
+						Name:      "Layer 2",
+						RenderedScheduleEntries: []pdApi.RenderedScheduleEntry{
+							{Start: "2021-10-25T03:30:00Z", End: "2021-10-25T08:30:00Z"},
+						},
+					},
+				},
+				FinalSchedule: pdApi.ScheduleLayer{
+					RenderedScheduleEntries: []pdApi.RenderedScheduleEntry{
+						{Start: "2021-10-25T03:30:00Z", End: "2021-10-25T08:30:00Z"},
+						{Start: "2021-10-25T08:30:00Z", End: "2021-10-25T13:30:00Z"},
+					},
+				},
+			}
+
 			listOnCallsResponse := &pdApi.ListOnCallsResponse{
 				OnCalls: []pdApi.OnCall{
 					{
 						Schedule: pdApi.Schedule{
 							APIObject: pdApi.APIObject{
+								ID:      "PRIMARY",
 								Summary: "0-SREP Weekday Primary",
 							},
 						},
@@ -58,26 +99,440 @@ var _ = Describe("kite oncall", func() {
 				},
 			}
 
+			mockClient.EXPECT().GetSchedule("PRIMARY", gomock.Any()).Return(scheduleResponse, nil).Times(1)
 			mockClient.EXPECT().ListOnCalls(gomock.Any()).Return(listOnCallsResponse, nil).Times(1)
-			// TODO: Fix unit tests
-			// expectedResponse := []pdcli.OncallLayer{
-			// 	{
-			// 		LayerId: "Layer 2 [APAC-W]",
-			// 		Users: []pdcli.OncallUser{
-			// 			{EscalationPolicy: "Openshift Escalation",
-			// 				OncallRole: "0-SREP Weekday Primary",
-			// 				Name:       "Red Hat SRE",
-			// 				Start:      "10-25-2021 03:30 UTC",
-			// 				End:        "10-25-2021 08:30 UTC",
-			// 			},
-			// 		},
-			// 	},
-			// }
-
-			_, err := pdcli.TeamSREOnCall(mockClient)
-			Expect(err).ToNot(HaveOccurred())
-			// TODO: Fix unit tests
-			// Expect(result).To(Equal(expectedResponse))
+
+			expectedResponse := []pdcli.OncallLayer{
+				{
+					LayerId: "Layer 2 [ APAC-W ]",
+					Start:   time.Date(2021, 10, 25, 3, 30, 0, 0, time.UTC),
+					End:     time.Date(2021, 10, 25, 8, 30, 0, 0, time.UTC),
+					Users: []pdcli.OncallUser{
+						{
+							EscalationPolicy: "Openshift Escalation",
+							OncallRole:       "0-SREP Weekday Primary",
+							ScheduleID:       "PRIMARY",
+							Name:             "Red Hat SRE",
+							Start:            "2021-10-25T03:30:00Z",
+							End:              "2021-10-25T08:30:00Z",
+						},
+					},
+				},
+				{
+					LayerId: "Layer 3",
+					Start:   time.Date(2021, 10, 25, 8, 30, 0, 0, time.UTC),
+					End:     time.Date(2021, 10, 25, 13, 30, 0, 0, time.UTC),
+				},
+			}
+
+			result, err := pdcli.TeamSREOnCall(mockClient, org, since, until)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expectedResponse))
+
+			// The current layer is picked by the time
+			Expect(pdcli.CurrentLayerIndex(result, time.Date(2021, 10, 25, 9, 0, 0, 0, time.UTC))).To(Equal(1))
+			Expect(pdcli.CurrentLayerIndex(result, time.Date(2021, 10, 25, 1, 0, 0, 0, time.UTC))).To(Equal(0))
+			Expect(pdcli.CurrentLayerIndex(result, time.Date(2021, 10, 25, 20, 0, 0, 0, time.UTC))).To(Equal(1))
+		})
+	})
+
+	When("an on-call time is given", func() {
+		// Wednesday
+		now := time.Date(2021, 10, 27, 10, 15, 0, 0, time.UTC)
+
+		It("parses RFC3339 and natural language times", func() {
+			times := map[string]time.Time{
+				"2021-10-30T03:00:00Z": time.Date(2021, 10, 30, 3, 0, 0, 0, time.UTC),
+				"2021-10-30 03:00":     time.Date(2021, 10, 30, 3, 0, 0, 0, time.UTC),
+				"now":                  now,
+				"in 3h":                now.Add(time.Hour * 3),
+				"-30m":                 now.Add(-time.Minute * 30),
+				"2 days ago":           now.AddDate(0, 0, -2),
+				"tomorrow":             time.Date(2021, 10, 28, 0, 0, 0, 0, time.UTC),
+				"tomorrow 9am":         time.Date(2021, 10, 28, 9, 0, 0, 0, time.UTC),
+				"Saturday 03:00 UTC":   time.Date(2021, 10, 30, 3, 0, 0, 0, time.UTC),
+				"wed 12pm":             time.Date(2021, 10, 27, 12, 0, 0, 0, time.UTC),
+				"next wednesday":       time.Date(2021, 11, 3, 0, 0, 0, 0, time.UTC),
+				"18:30":                time.Date(2021, 10, 27, 18, 30, 0, 0, time.UTC),
+			}
+
+			for input, expected := range times {
+				result, err := utils.ParseTime(input, now)
+
+				Expect(err).ToNot(HaveOccurred(), input)
+				Expect(result).To(Equal(expected), input)
+			}
+		})
+
+		It("throws an error for an invalid time", func() {
+			for _, input := range []string{"someday", "next", "25:00", "13pm", "saturday noon"} {
+				_, err := utils.ParseTime(input, now)
+
+				Expect(err).To(HaveOccurred(), input)
+			}
+		})
+
+		It("returns the on-call window for the given options", func() {
+			since, until, at, err := pdcli.OncallWindow("saturday 03:00", "", "", now)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(at).To(Equal(time.Date(2021, 10, 30, 3, 0, 0, 0, time.UTC)))
+			Expect(since).To(Equal(at.Add(-time.Hour * 11)))
+			Expect(until).To(Equal(at.Add(time.Hour * 13)))
+
+			since, until, at, err = pdcli.OncallWindow("", "", "in 7 days", now)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(since).To(Equal(now))
+			Expect(until).To(Equal(now.AddDate(0, 0, 7)))
+			Expect(at).To(Equal(now))
+
+			_, _, _, err = pdcli.OncallWindow("now", "tomorrow", "", now)
+
+			Expect(err).To(HaveOccurred())
+
+			_, _, _, err = pdcli.OncallWindow("", "tomorrow", "today", now)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the timestamps are displayed", func() {
+		AfterEach(func() {
+			err := utils.SetTimeDisplay("", false)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("formats timestamps with an offset in the display timezone", func() {
+			err := utils.SetTimeDisplay("Asia/Kolkata", false)
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err := utils.FormatTimestamp("2021-10-25T03:30:00+02:00")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("10-25-2021 07:00 IST"))
+
+			err = utils.SetTimeDisplay("UTC", false)
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err = utils.FormatTimestamp("2021-10-25T03:30:00Z")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("10-25-2021 03:30 UTC"))
+		})
+
+		It("formats times relative to now", func() {
+			now := time.Date(2021, 10, 25, 3, 30, 0, 0, time.UTC)
+
+			Expect(utils.RelativeTime(now.Add(time.Hour*3), now)).To(Equal("in 3h"))
+			Expect(utils.RelativeTime(now.Add(-time.Minute*12), now)).To(Equal("12m ago"))
+			Expect(utils.RelativeTime(now, now)).To(Equal("now"))
+		})
+
+		It("throws an error for an invalid timezone", func() {
+			err := utils.SetTimeDisplay("Mars/Olympus", false)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the on-call shifts are exported", func() {
+		now := time.Date(2021, 10, 20, 12, 0, 0, 0, time.UTC)
+
+		shift := pdcli.OncallUser{
+			EscalationPolicy:   "Openshift Escalation, Primary",
+			EscalationPolicyID: "POLICY1",
+			OncallRole:         "0-SREP Weekday Primary",
+			ScheduleID:         "PRIMARY",
+			Name:               "Red Hat SRE",
+			UserID:             "USER001",
+			Start:              "2021-10-25T09:00:00+05:30",
+			End:                "2021-10-25T08:30:00Z",
+		}
+
+		It("writes an iCalendar event for each shift", func() {
+			calendar := pdcli.ICS([]pdcli.OncallUser{shift}, now)
+
+			Expect(calendar).To(HavePrefix("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+			Expect(calendar).To(HaveSuffix("END:VEVENT\r\nEND:VCALENDAR\r\n"))
+			Expect(calendar).To(ContainSubstring("DTSTART:20211025T033000Z\r\n"))
+			Expect(calendar).To(ContainSubstring("DTEND:20211025T083000Z\r\n"))
+			Expect(calendar).To(ContainSubstring("DTSTAMP:20211020T120000Z\r\n"))
+			Expect(calendar).To(ContainSubstring("SUMMARY:On-call: 0-SREP Weekday Primary\r\n"))
+			Expect(calendar).To(ContainSubstring("CATEGORIES:Openshift Escalation\\, Primary\r\n"))
+
+			for _, line := range strings.Split(calendar, "\r\n") {
+				Expect(len(line)).To(BeNumerically("<=", 75))
+			}
+		})
+
+		It("skips the shifts without a start or an end", func() {
+			permanent := shift
+			permanent.Start = ""
+			permanent.End = ""
+
+			calendar := pdcli.ICS([]pdcli.OncallUser{permanent, shift}, now)
+
+			Expect(strings.Count(calendar, "BEGIN:VEVENT")).To(Equal(1))
+			Expect(calendar).To(ContainSubstring("DTSTART:20211025T033000Z\r\n"))
+		})
+
+		It("uses stable event UIDs", func() {
+			first := pdcli.ICS([]pdcli.OncallUser{shift}, now)
+			second := pdcli.ICS([]pdcli.OncallUser{shift}, now.Add(time.Hour*24))
+
+			uid := regexp.MustCompile(`UID:(\S+)`)
+
+			Expect(uid.FindString(first)).ToNot(BeEmpty())
+			Expect(uid.FindString(first)).To(Equal(uid.FindString(second)))
+
+			moved := shift
+			moved.Start = "2021-10-25T04:30:00Z"
+			moved.End = "2021-10-25T09:30:00Z"
+			third := pdcli.ICS([]pdcli.OncallUser{moved}, now)
+
+			Expect(uid.FindString(third)).To(Equal(uid.FindString(first)))
+		})
+
+		It("uses a distinct event UID for each shift of a schedule", func() {
+			next := shift
+			next.Start = "2021-10-26T03:30:00Z"
+			next.End = "2021-10-26T08:30:00Z"
+
+			calendar := pdcli.ICS([]pdcli.OncallUser{next, shift}, now)
+			uids := regexp.MustCompile(`UID:(\S+)`).FindAllString(calendar, -1)
+
+			Expect(uids).To(HaveLen(2))
+			Expect(uids[0]).ToNot(Equal(uids[1]))
+			Expect(uids[0]).To(Equal(regexp.MustCompile(`UID:(\S+)`).FindString(pdcli.ICS([]pdcli.OncallUser{shift}, now))))
+		})
+
+		It("fetches all the pages of the next on-call shifts within 90 days", func() {
+			mockClient.EXPECT().ListOnCalls(gomock.Any()).DoAndReturn(func(opts pdApi.ListOnCallOptions) (*pdApi.ListOnCallsResponse, error) {
+				Expect(opts.UserIDs).To(Equal([]string{"USER001"}))
+				Expect(opts.Since).To(Equal("2021-10-20T12:00:00Z"))
+				Expect(opts.Until).To(Equal("2022-01-18T12:00:00Z"))
+				Expect(opts.Offset).To(BeZero())
+
+				return &pdApi.ListOnCallsResponse{
+					APIListObject: pdApi.APIListObject{More: true},
+					OnCalls:       []pdApi.OnCall{{Start: "2021-10-25T03:30:00Z", End: "2021-10-25T08:30:00Z"}},
+				}, nil
+			}).Times(1)
+
+			mockClient.EXPECT().ListOnCalls(gomock.Any()).DoAndReturn(func(opts pdApi.ListOnCallOptions) (*pdApi.ListOnCallsResponse, error) {
+				Expect(opts.Offset).To(Equal(opts.Limit))
+
+				return &pdApi.ListOnCallsResponse{
+					OnCalls: []pdApi.OnCall{{Start: "2021-10-26T03:30:00Z", End: "2021-10-26T08:30:00Z"}},
+				}, nil
+			}).Times(1)
+
+			shifts, err := pdcli.UserNextOncallSchedule(mockClient, "USER001", now)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shifts).To(HaveLen(2))
+			Expect(shifts[1].Start).To(Equal("2021-10-26T03:30:00Z"))
+		})
+	})
+
+	When("schedule overrides are managed", func() {
+		org := config.Org{PrimaryScheduleID: "PRIMARY", WeekendScheduleID: "WEEKEND"}
+		start := time.Date(2021, 10, 25, 0, 0, 0, 0, time.UTC)
+		end := start.Add(time.Hour * 12)
+
+		override := pdcli.Override{
+			ID:         "OVERRIDE1",
+			ScheduleID: "PRIMARY",
+			Start:      "2021-10-25T00:00:00Z",
+			End:        "2021-10-25T12:00:00Z",
+		}
+
+		It("resolves the configured schedules by role or ID", func() {
+			scheduleID, err := pdcli.ResolveSchedule(org, "Primary")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scheduleID).To(Equal("PRIMARY"))
+
+			scheduleID, err = pdcli.ResolveSchedule(org, "WEEKEND")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scheduleID).To(Equal("WEEKEND"))
+
+			_, err = pdcli.ResolveSchedule(org, "secondary")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("creates an override for the given user and time range", func() {
+			mockClient.EXPECT().CreateOverride("PRIMARY", pdApi.Override{
+				Start: "2021-10-25T00:00:00Z",
+				End:   "2021-10-25T12:00:00Z",
+				User:  pdApi.APIObject{ID: "USER001", Type: "user_reference"},
+			}).Return(&pdApi.Override{
+				ID:    "OVERRIDE1",
+				Start: "2021-10-25T00:00:00Z",
+				End:   "2021-10-25T12:00:00Z",
+				User:  pdApi.APIObject{ID: "USER001", Summary: "Red Hat SRE"},
+			}, nil).Times(1)
+
+			created, err := pdcli.CreateOverride(mockClient, "PRIMARY", "USER001", start, end)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created.ID).To(Equal("OVERRIDE1"))
+			Expect(created.User).To(Equal("Red Hat SRE"))
+		})
+
+		It("throws an error if the override ends before it starts", func() {
+			_, err := pdcli.CreateOverride(mockClient, "PRIMARY", "USER001", end, start)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("lists the overrides of the schedules by start time", func() {
+			mockClient.EXPECT().ListOverrides("PRIMARY", gomock.Any()).Return(&pdApi.ListOverridesResponse{
+				Overrides: []pdApi.Override{{ID: "OVERRIDE2", Start: "2021-10-26T00:00:00Z", End: "2021-10-26T12:00:00Z"}},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListOverrides("WEEKEND", gomock.Any()).Return(&pdApi.ListOverridesResponse{
+				Overrides: []pdApi.Override{{ID: "OVERRIDE1", Start: "2021-10-25T00:00:00Z", End: "2021-10-25T12:00:00Z"}},
+			}, nil).Times(1)
+
+			overrides, err := pdcli.ListOverrides(mockClient, org, org.ScheduleIDs(), start, start.AddDate(0, 0, 30))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(overrides).To(HaveLen(2))
+			Expect(overrides[0].ID).To(Equal("OVERRIDE1"))
+			Expect(overrides[0].Schedule).To(Equal("weekend"))
+			Expect(overrides[1].Schedule).To(Equal("primary"))
+		})
+
+		It("sorts the overrides by start time whatever their timezone offset", func() {
+			mockClient.EXPECT().ListOverrides("PRIMARY", gomock.Any()).Return(&pdApi.ListOverridesResponse{
+				Overrides: []pdApi.Override{{ID: "OVERRIDE1", Start: "2021-10-25T05:00:00+05:30", End: "2021-10-25T12:00:00+05:30"}},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListOverrides("WEEKEND", gomock.Any()).Return(&pdApi.ListOverridesResponse{
+				Overrides: []pdApi.Override{{ID: "OVERRIDE2", Start: "2021-10-25T00:00:00Z", End: "2021-10-25T12:00:00Z"}},
+			}, nil).Times(1)
+
+			overrides, err := pdcli.ListOverrides(mockClient, org, org.ScheduleIDs(), start, start.AddDate(0, 0, 30))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(overrides).To(HaveLen(2))
+			Expect(overrides[0].ID).To(Equal("OVERRIDE1"))
+			Expect(overrides[1].ID).To(Equal("OVERRIDE2"))
+		})
+
+		It("returns the time ranges not covered by the schedule layers", func() {
+			schedule := &pdApi.Schedule{
+				ScheduleLayers: []pdApi.ScheduleLayer{
+					{RenderedScheduleEntries: []pdApi.RenderedScheduleEntry{
+						{Start: "2021-10-24T20:00:00Z", End: "2021-10-25T04:00:00Z"},
+						{Start: "2021-10-25T08:00:00Z", End: "2021-10-25T10:00:00Z"},
+					}},
+				},
+			}
+
+			gaps, err := pdcli.CoverageGaps(schedule, start, end)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gaps).To(Equal([]pdcli.Gap{
+				{Start: start.Add(time.Hour * 4), End: start.Add(time.Hour * 8)},
+				{Start: start.Add(time.Hour * 10), End: end},
+			}))
+		})
+
+		It("does not delete an override which leaves a gap", func() {
+			mockClient.EXPECT().GetSchedule("PRIMARY", gomock.Any()).Return(&pdApi.Schedule{}, nil).Times(1)
+			mockClient.EXPECT().DeleteOverride(gomock.Any(), gomock.Any()).Times(0)
+
+			err := pdcli.DeleteOverride(mockClient, override, false)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("deletes an override when the schedule is covered", func() {
+			mockClient.EXPECT().GetSchedule("PRIMARY", gomock.Any()).Return(&pdApi.Schedule{
+				ScheduleLayers: []pdApi.ScheduleLayer{
+					{RenderedScheduleEntries: []pdApi.RenderedScheduleEntry{
+						{Start: "2021-10-25T00:00:00Z", End: "2021-10-25T12:00:00Z"},
+					}},
+				},
+			}, nil).Times(1)
+			mockClient.EXPECT().DeleteOverride("PRIMARY", "OVERRIDE1").Return(nil).Times(1)
+
+			err := pdcli.DeleteOverride(mockClient, override, false)
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("deletes an override which leaves a gap when forced", func() {
+			mockClient.EXPECT().DeleteOverride("PRIMARY", "OVERRIDE1").Return(nil).Times(1)
+
+			err := pdcli.DeleteOverride(mockClient, override, true)
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	When("kite oncall --service is run", func() {
+		service := pdApi.Service{
+			APIObject:        pdApi.APIObject{ID: "PSERV01"},
+			Name:             "osd-cluster-1",
+			EscalationPolicy: pdApi.EscalationPolicy{APIObject: pdApi.APIObject{ID: "POLICY1"}},
+		}
+
+		policy := &pdApi.EscalationPolicy{
+			APIObject: pdApi.APIObject{ID: "POLICY1"},
+			Name:      "Other Team Escalation",
+			EscalationRules: []pdApi.EscalationRule{
+				{Delay: 15, Targets: []pdApi.APIObject{{Summary: "Other Team Primary"}}},
+				{Delay: 30, Targets: []pdApi.APIObject{{Summary: "Other Team Manager"}}},
+			},
+		}
+
+		It("shows the on-call chain for every level of the service escalation policy", func() {
+			mockClient.EXPECT().ListServices(pdApi.ListServiceOptions{Query: "OSD-Cluster-1"}).Return(&pdApi.ListServiceResponse{
+				Services: []pdApi.Service{{Name: "osd-cluster-10"}, service},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().GetEscalationPolicy("POLICY1", gomock.Any()).Return(policy, nil).Times(1)
+
+			mockClient.EXPECT().ListOnCalls(gomock.Any()).Return(&pdApi.ListOnCallsResponse{
+				OnCalls: []pdApi.OnCall{
+					{User: pdApi.User{Summary: "Other SRE"}, EscalationLevel: 1},
+				},
+			}, nil).Times(1)
+
+			chain, err := pdcli.ServiceEscalationChain(mockClient, "OSD-Cluster-1")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(chain.Service).To(Equal("osd-cluster-1"))
+			Expect(chain.EscalationPolicy).To(Equal("Other Team Escalation"))
+			Expect(chain.Levels).To(HaveLen(2))
+			Expect(chain.Levels[0].Targets).To(Equal([]string{"Other Team Primary"}))
+			Expect(chain.Levels[0].Users).To(HaveLen(1))
+			Expect(chain.Levels[0].Users[0].Name).To(Equal("Other SRE"))
+			Expect(chain.Levels[1].Level).To(Equal(uint(2)))
+			Expect(chain.Levels[1].Users).To(BeEmpty())
+		})
+
+		It("resolves a service by ID", func() {
+			mockClient.EXPECT().GetService("PSERV01", gomock.Any()).Return(&service, nil).Times(1)
+
+			resolved, err := pdcli.ResolveService(mockClient, "PSERV01")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved.Name).To(Equal("osd-cluster-1"))
+		})
+
+		It("throws an error if the service name is ambiguous", func() {
+			mockClient.EXPECT().ListServices(gomock.Any()).Return(&pdApi.ListServiceResponse{
+				Services: []pdApi.Service{{Name: "osd-cluster-10"}, {Name: "osd-cluster-11"}},
+			}, nil).Times(1)
+
+			_, err := pdcli.ResolveService(mockClient, "osd-cluster")
+
+			Expect(err).To(HaveOccurred())
 		})
 	})
 })
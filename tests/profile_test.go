@@ -0,0 +1,84 @@
+package tests
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("kite profile", func() {
+	configFile := `{
+  "api_key": "y_NbAkKc66ryYTWUXYEu",
+  "timezone": "Europe/Prague",
+  "profiles": {
+    "stage": {
+      "api_key": "u+AbAkKc66ryYTWUXYEu",
+      "teams": [{"id": "PSTAGE1", "name": "Stage SRE"}]
+    }
+  }
+}`
+
+	It("lists the profiles and marks the active one", func() {
+		result := NewCommand().Config(configFile).Args("profile", "list").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.OutString()).To(Equal("* default\n  stage\n"))
+	})
+
+	It("marks the profile selected by the environment as active", func() {
+		result := NewCommand().Config(configFile).Env("KITE_PROFILE", "stage").Args("profile", "list").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.OutString()).To(Equal("  default\n* stage\n"))
+	})
+
+	It("reads the configuration of the profile selected by the flag", func() {
+		result := NewCommand().Config(configFile).Args("config", "view", "--show-origin", "--profile", "stage").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.OutString()).To(MatchRegexp(`profile\s+stage\s+flag --profile`))
+		Expect(result.OutString()).To(MatchRegexp(`teams\s+PSTAGE1\s+file `))
+		Expect(result.OutString()).To(MatchRegexp(`timezone\s+UTC\s+default`))
+	})
+
+	It("sets the active profile", func() {
+		result := NewCommand().Config(configFile).Args("profile", "use", "stage").Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).To(MatchJSON(`{
+  "version": 1,
+  "api_key": "y_NbAkKc66ryYTWUXYEu",
+  "timezone": "Europe/Prague",
+  "profile": "stage",
+  "profiles": {
+    "stage": {
+      "api_key": "u+AbAkKc66ryYTWUXYEu",
+      "teams": [{"id": "PSTAGE1", "name": "Stage SRE"}]
+    }
+  }
+}`))
+	})
+
+	It("fails to use a profile which doesn't exist", func() {
+		result := NewCommand().Config(configFile).Args("profile", "use", "prod").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("profile 'prod' not found"))
+	})
+
+	It("deletes a profile and falls back to the default profile", func() {
+		result := NewCommand().
+			Config(`{"api_key": "y_NbAkKc66ryYTWUXYEu", "profile": "stage", "profiles": {"stage": {"api_key": "u+AbAkKc66ryYTWUXYEu"}}}`).
+			Args("profile", "delete", "stage").
+			Run()
+
+		Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+		Expect(result.ConfigString()).To(MatchJSON(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`))
+	})
+
+	It("fails to delete the default profile", func() {
+		result := NewCommand().Config(configFile).Args("profile", "delete", "default").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("the default profile can't be deleted"))
+	})
+})
@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"bytes"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	mockpd "github.com/openshift/pagerduty-short-circuiter/pkg/client/mock"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	report "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/report"
+)
+
+// logEntry returns a pagerduty log entry of the given type created at the given time.
+func logEntry(entryType string, createdAt string, agent string) pdApi.LogEntry {
+	return pdApi.LogEntry{
+		CommonLogEntryField: pdApi.CommonLogEntryField{
+			APIObject: pdApi.APIObject{Type: entryType},
+			CreatedAt: createdAt,
+			Agent:     pdApi.Agent{Summary: agent},
+		},
+	}
+}
+
+var _ = Describe("kite report", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockClient *mockpd.MockPagerDutyClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = mockpd.NewMockPagerDutyClient(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	since := time.Date(2021, 10, 18, 0, 0, 0, 0, time.UTC)
+	until := since.Add(time.Hour * 24 * 7)
+
+	Describe("percentiles", func() {
+		It("computes the nearest-rank percentiles", func() {
+			var durations []time.Duration
+
+			for i := 100; i > 0; i-- {
+				durations = append(durations, time.Duration(i)*time.Minute)
+			}
+
+			p := report.ComputePercentiles(durations)
+
+			Expect(p).To(Equal(report.Percentiles{
+				Samples: 100,
+				P50:     50 * time.Minute,
+				P90:     90 * time.Minute,
+				P95:     95 * time.Minute,
+				P99:     99 * time.Minute,
+			}))
+		})
+
+		It("returns the only sample for all the percentiles", func() {
+			p := report.ComputePercentiles([]time.Duration{time.Minute})
+
+			Expect(p.P50).To(Equal(time.Minute))
+			Expect(p.P99).To(Equal(time.Minute))
+		})
+
+		It("returns no samples when there are no durations", func() {
+			Expect(report.ComputePercentiles(nil)).To(Equal(report.Percentiles{}))
+		})
+	})
+
+	Describe("counts", func() {
+		It("counts the incidents by alert, cluster, hour and acknowledger", func() {
+			incidents := []report.Incident{
+				{
+					CreatedAt:      time.Date(2021, 10, 18, 3, 10, 0, 0, time.UTC),
+					Alerts:         []string{"ClusterDown", "ClusterDown"},
+					Clusters:       []string{"cluster-a"},
+					Acknowledged:   true,
+					AcknowledgedBy: "Alice",
+					TimeToAck:      time.Minute,
+				},
+				{
+					CreatedAt: time.Date(2021, 10, 19, 3, 50, 0, 0, time.UTC),
+					Alerts:    []string{"ClusterDown"},
+					Clusters:  []string{"cluster-b"},
+					Resolved:  true,
+				},
+				{
+					CreatedAt:      time.Date(2021, 10, 19, 14, 0, 0, 0, time.UTC),
+					Alerts:         []string{"KubeAPIErrorBudgetBurn"},
+					Clusters:       []string{"cluster-a"},
+					Acknowledged:   true,
+					AcknowledgedBy: "Alice",
+				},
+			}
+
+			r := report.Compute(incidents, since, until)
+
+			Expect(r.Incidents).To(Equal(3))
+			Expect(r.MTTA.Samples).To(Equal(2))
+			Expect(r.MTTR.Samples).To(Equal(1))
+			Expect(r.Alerts).To(Equal([]report.Count{{Key: "ClusterDown", Count: 2}, {Key: "KubeAPIErrorBudgetBurn", Count: 1}}))
+			Expect(r.Clusters).To(Equal([]report.Count{{Key: "cluster-a", Count: 2}, {Key: "cluster-b", Count: 1}}))
+			Expect(r.AckBy).To(Equal([]report.Count{{Key: "Alice", Count: 2}}))
+			Expect(r.Hours).To(HaveLen(24))
+			Expect(r.Hours[3]).To(Equal(report.Count{Key: "03", Count: 2}))
+			Expect(r.Hours[14]).To(Equal(report.Count{Key: "14", Count: 1}))
+		})
+	})
+
+	When("the report is generated", func() {
+		It("fetches all the pages of incidents and their log entries", func() {
+			cfg := &config.Config{Teams: []config.Team{{ID: "TEAM1", Name: "Platform SRE"}}}
+			user := &pdApi.User{APIObject: pdApi.APIObject{ID: "USER001"}}
+
+			first := incident("INCIDENT1")
+			first.CreatedAt = "2021-10-18T03:00:00Z"
+
+			second := incident("INCIDENT2")
+			second.CreatedAt = "2021-10-19T03:00:00Z"
+
+			gomock.InOrder(
+				mockClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(opts pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+					Expect(opts.Statuses).To(ConsistOf("triggered", "acknowledged", "resolved"))
+					Expect(opts.Since).To(Equal("2021-10-18T00:00:00Z"))
+					Expect(opts.Offset).To(BeZero())
+
+					return &pdApi.ListIncidentsResponse{APIListObject: pdApi.APIListObject{More: true}, Incidents: []pdApi.Incident{first}}, nil
+				}),
+				mockClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(opts pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+					Expect(opts.Offset).To(Equal(uint(100)))
+
+					return &pdApi.ListIncidentsResponse{Incidents: []pdApi.Incident{second}}, nil
+				}),
+			)
+
+			mockClient.EXPECT().ListIncidentAlerts("INCIDENT1").Return(&pdApi.ListAlertsResponse{
+				Alerts: []pdApi.IncidentAlert{alert("INCIDENT1", "my-service-id", "ClusterDown", "cluster-id", "resolved")},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentAlerts("INCIDENT2").Return(&pdApi.ListAlertsResponse{
+				Alerts: []pdApi.IncidentAlert{{APIObject: pdApi.APIObject{Summary: "Manual incident"}}},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().GetService("my-service-id", gomock.Any()).Return(&pdApi.Service{Description: "my-cluster-name"}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentLogEntries("INCIDENT1", gomock.Any()).Return(&pdApi.ListIncidentLogEntriesResponse{
+				LogEntries: []pdApi.LogEntry{
+					logEntry("resolve_log_entry", "2021-10-18T04:00:00Z", "Alice"),
+					logEntry("acknowledge_log_entry", "2021-10-18T03:20:00Z", "Bob"),
+					logEntry("acknowledge_log_entry", "2021-10-18T03:05:00Z", "Alice"),
+				},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentLogEntries("INCIDENT2", gomock.Any()).Return(&pdApi.ListIncidentLogEntriesResponse{}, nil).Times(1)
+
+			r, err := report.GenerateReport(mockClient, cfg, user, since, until)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Incidents).To(Equal(2))
+			Expect(r.MTTA).To(Equal(report.Percentiles{Samples: 1, P50: 5 * time.Minute, P90: 5 * time.Minute, P95: 5 * time.Minute, P99: 5 * time.Minute}))
+			Expect(r.MTTR.P50).To(Equal(time.Hour))
+			Expect(r.AckBy).To(Equal([]report.Count{{Key: "Alice", Count: 1}}))
+			Expect(r.Alerts).To(ConsistOf(report.Count{Key: "ClusterDown", Count: 1}, report.Count{Key: "Manual incident", Count: 1}))
+			Expect(r.Clusters).To(Equal([]report.Count{{Key: "my-cluster-name", Count: 1}}))
+		})
+	})
+
+	When("the report is rendered", func() {
+		r := report.Compute([]report.Incident{{
+			CreatedAt:      since,
+			Alerts:         []string{"ClusterDown"},
+			Acknowledged:   true,
+			AcknowledgedBy: "Alice",
+			TimeToAck:      90 * time.Second,
+		}}, since, until)
+
+		It("writes CSV rows of section, key and value", func() {
+			var b bytes.Buffer
+
+			Expect(report.Render(&b, r, report.CSVFormat, 10)).To(Succeed())
+			Expect(b.String()).To(HavePrefix("section,key,value\nincidents,total,1\nmtta,samples,1\nmtta,p50,90\n"))
+			Expect(b.String()).To(ContainSubstring("alert,ClusterDown,1\n"))
+		})
+
+		It("writes the percentiles in seconds in JSON", func() {
+			var b bytes.Buffer
+
+			Expect(report.Render(&b, r, report.JSONFormat, 10)).To(Succeed())
+			Expect(b.String()).To(ContainSubstring(`"p50_seconds": 90`))
+		})
+
+		It("writes tables with N/A percentiles when there are no samples", func() {
+			var b bytes.Buffer
+
+			Expect(report.Render(&b, r, report.TableFormat, 10)).To(Succeed())
+			Expect(b.String()).To(ContainSubstring("1m30s"))
+			Expect(b.String()).To(MatchRegexp(`MTTR\s+0\s+N/A`))
+		})
+
+		It("fails on an invalid format", func() {
+			Expect(report.Render(&bytes.Buffer{}, r, "xml", 10)).ToNot(Succeed())
+		})
+	})
+
+	When("the cluster history is searched", func() {
+		cfg := &config.Config{Teams: []config.Team{{ID: "TEAM1", Name: "Platform SRE"}}, HistoryDays: 7}
+		user := &pdApi.User{APIObject: pdApi.APIObject{ID: "USER001"}}
+		now := until
+
+		It("lists the incidents of the same cluster over the history window", func() {
+			current := incident("CURRENT")
+
+			resolved := incident("INCIDENT1")
+			resolved.Title = "ClusterDown"
+			resolved.Status = "resolved"
+			resolved.CreatedAt = "2021-10-20T03:00:00Z"
+			resolved.LastStatusChangeAt = "2021-10-20T04:00:00Z"
+
+			other := incident("INCIDENT2")
+			other.CreatedAt = "2021-10-21T03:00:00Z"
+
+			latest := incident("INCIDENT3")
+			latest.Status = "acknowledged"
+			latest.CreatedAt = "2021-10-22T03:00:00Z"
+
+			mockClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(opts pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+				Expect(opts.Statuses).To(ContainElement("resolved"))
+				Expect(opts.Since).To(Equal("2021-10-18T00:00:00Z"))
+				Expect(opts.ServiceIDs).To(Equal([]string{"my-service-id"}))
+
+				return &pdApi.ListIncidentsResponse{Incidents: []pdApi.Incident{current, resolved, other, latest}}, nil
+			}).Times(1)
+
+			mockClient.EXPECT().ListIncidentAlerts("INCIDENT1").Return(&pdApi.ListAlertsResponse{
+				Alerts: []pdApi.IncidentAlert{alert("INCIDENT1", "my-service-id", "ClusterDown", "cluster-id", "resolved")},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentAlerts("INCIDENT2").Return(&pdApi.ListAlertsResponse{
+				Alerts: []pdApi.IncidentAlert{alert("INCIDENT2", "other-service-id", "ClusterDown", "other-cluster-id", "triggered")},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentAlerts("INCIDENT3").Return(&pdApi.ListAlertsResponse{
+				Alerts: []pdApi.IncidentAlert{alert("INCIDENT3", "my-service-id", "ClusterDown", "cluster-id", "triggered")},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().GetService("my-service-id", gomock.Any()).Return(&pdApi.Service{Description: "my-cluster-name"}, nil).Times(2)
+			mockClient.EXPECT().GetService("other-service-id", gomock.Any()).Return(&pdApi.Service{Description: "other-cluster-name"}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentNotes("INCIDENT1").Return([]pdApi.IncidentNote{
+				{Content: "Restarted the router pods", CreatedAt: "2021-10-20T03:50:00Z", User: pdApi.APIObject{Summary: "Alice"}},
+			}, nil).Times(1)
+
+			mockClient.EXPECT().ListIncidentNotes("INCIDENT3").Return(nil, nil).Times(1)
+
+			history, err := report.ClusterIncidentHistory(mockClient, cfg, user, pdcli.Alert{IncidentID: "CURRENT", ClusterID: "cluster-id", ClusterName: "my-cluster-name", ServiceID: "my-service-id"}, now)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(history.Incidents).To(HaveLen(2))
+			Expect(history.Incidents[0].ID).To(Equal("INCIDENT3"))
+			Expect(history.Incidents[1].ResolvedAt).To(Equal("2021-10-20T04:00:00Z"))
+			Expect(history.Incidents[1].Notes).To(Equal([]report.Note{{Author: "Alice", Content: "Restarted the router pods", CreatedAt: "2021-10-20T03:50:00Z"}}))
+			Expect(history.LastOccurrence()).To(Equal("2021-10-22T03:00:00Z"))
+			Expect(history.Frequency()).To(Equal([]report.Count{{Key: "ClusterDown", Count: 2}}))
+
+			text := report.ParseClusterHistory(history)
+
+			Expect(text).To(ContainSubstring("* Incidents: 2\n"))
+			Expect(text).To(ContainSubstring("* 2x ClusterDown\n"))
+			Expect(text).To(ContainSubstring("Alice: Restarted the router pods"))
+		})
+
+		It("fails when the alert has no cluster", func() {
+			_, err := report.ClusterIncidentHistory(mockClient, cfg, user, pdcli.Alert{ClusterID: "N/A", ClusterName: "N/A"}, now)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
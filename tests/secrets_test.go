@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secrets"
+)
+
+var _ = Describe("secret backends", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = os.MkdirTemp("", "kite-secrets-*.d")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	passphrase := func(p string) func() (string, error) {
+		return func() (string, error) { return p, nil }
+	}
+
+	When("the secrets are stored in an encrypted file", func() {
+		It("stores and resolves the secrets", func() {
+			opts := secrets.Options{File: filepath.Join(dir, "secrets.enc"), Passphrase: passphrase("correct horse")}
+
+			ref, err := secrets.Store(secrets.FileBackend, "pagerduty_api_key", "y_NbAkKc66ryYTWUXYEu", opts)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ref).To(Equal("file:pagerduty_api_key"))
+
+			data, err := os.ReadFile(opts.File)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).ToNot(ContainSubstring("y_NbAkKc66ryYTWUXYEu"))
+
+			secret, err := secrets.Resolve(ref, opts)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret).To(Equal("y_NbAkKc66ryYTWUXYEu"))
+		})
+
+		It("fails to decrypt the file with a wrong passphrase", func() {
+			opts := secrets.Options{File: filepath.Join(dir, "secrets.enc"), Passphrase: passphrase("correct horse")}
+
+			_, err := secrets.Store(secrets.FileBackend, "github_token", "token", opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			opts.Passphrase = passphrase("wrong")
+
+			_, err = secrets.Resolve("file:github_token", opts)
+			Expect(err).To(MatchError(ContainSubstring("please check the passphrase")))
+		})
+
+		It("deletes the secrets", func() {
+			opts := secrets.Options{File: filepath.Join(dir, "secrets.enc"), Passphrase: passphrase("correct horse")}
+
+			backend, err := secrets.NewBackend(secrets.FileBackend, opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(backend.Set("github_token", "token")).To(Succeed())
+			Expect(backend.Delete("github_token")).To(Succeed())
+
+			_, err = secrets.Resolve("file:github_token", opts)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the secrets are read from a command", func() {
+		It("resolves the secret printed by the command", func() {
+			secret, err := secrets.Resolve("command:echo y_NbAkKc66ryYTWUXYEu", secrets.Options{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret).To(Equal("y_NbAkKc66ryYTWUXYEu"))
+		})
+
+		It("fails when the command fails", func() {
+			_, err := secrets.Resolve("command:exit 1", secrets.Options{})
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("can't store secrets", func() {
+			_, err := secrets.Store(secrets.CommandBackend, "echo token", "token", secrets.Options{})
+
+			Expect(err).To(MatchError(ContainSubstring("read-only")))
+		})
+	})
+
+	It("fails on invalid references and backends", func() {
+		_, err := secrets.Resolve("pagerduty_api_key", secrets.Options{})
+		Expect(err).To(MatchError(ContainSubstring("invalid secret reference")))
+
+		_, err = secrets.Resolve("vault:pagerduty_api_key", secrets.Options{})
+		Expect(err).To(MatchError(ContainSubstring("invalid secret backend")))
+	})
+})
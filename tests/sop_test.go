@@ -0,0 +1,336 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+// git runs a git command in the directory.
+func git(dir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=kite", "-c", "user.email=kite@example.com"}, args...)...)
+	out, err := cmd.CombinedOutput()
+
+	ExpectWithOffset(1, err).ToNot(HaveOccurred(), string(out))
+}
+
+var _ = Describe("SOP", func() {
+	Describe("URL parsing", func() {
+		It("returns the document of a GitHub markdown URL", func() {
+			owner, repo, ref, path, err := utils.ParseGitHubURL("https://github.com/openshift/ops-sop/blob/master/v4/alerts/ClusterDown.md#troubleshooting")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect([]string{owner, repo, ref, path}).To(Equal([]string{"openshift", "ops-sop", "master", "v4/alerts/ClusterDown.md"}))
+		})
+
+		It("accepts the tree URLs", func() {
+			_, _, ref, path, err := utils.ParseGitHubURL("https://github.com/openshift/ops-sop/tree/main/README.md")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ref).To(Equal("main"))
+			Expect(path).To(Equal("README.md"))
+		})
+
+		It("rejects the URLs which are not GitHub markdown documents", func() {
+			for _, url := range []string{
+				"https://docs.example.com/sop.md",
+				"https://github.com/openshift/ops-sop/blob/master/v4/alerts/",
+				"https://github.com/alert.md",
+				"N/A",
+			} {
+				_, _, _, _, err := utils.ParseGitHubURL(url)
+
+				Expect(err).To(HaveOccurred(), url)
+			}
+		})
+	})
+
+	It("is fetched from GitHub only when a GitHub token is configured", func() {
+		Expect((&config.Config{}).HasGitHub()).To(BeFalse())
+		Expect((&config.Config{AccessToken: "ghp_token"}).HasGitHub()).To(BeTrue())
+	})
+
+	Describe("local clone", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+
+			dir, err = os.MkdirTemp("", "kite-sop-*")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(dir, "origin", "v4", "alerts"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "origin", "v4", "alerts", "ClusterDown.md"), []byte("# ClusterDown\n"), 0644)).To(Succeed())
+			git(filepath.Join(dir, "origin"), "init", "-q")
+			git(filepath.Join(dir, "origin"), "add", ".")
+			git(filepath.Join(dir, "origin"), "commit", "-q", "-m", "Add ClusterDown")
+			git(dir, "clone", "-q", "origin", "ops-sop")
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("reads the documents of the SOP repository", func() {
+			content, err := sop.ReadLocal(filepath.Join(dir, "ops-sop"), "openshift", "ops-sop", "v4/alerts/ClusterDown.md")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(Equal("# ClusterDown\n"))
+		})
+
+		It("doesn't read the documents of other repositories", func() {
+			_, err := sop.ReadLocal(filepath.Join(dir, "ops-sop"), "openshift", "other-repo", "v4/alerts/ClusterDown.md")
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("doesn't read the files outside of the clone", func() {
+			_, err := sop.ReadLocal(filepath.Join(dir, "ops-sop"), "openshift", "ops-sop", "../origin/v4/alerts/ClusterDown.md")
+
+			Expect(err).To(MatchError(ContainSubstring("invalid document path")))
+		})
+
+		It("pulls the clone", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "origin", "README.md"), []byte("# SOPs\n"), 0644)).To(Succeed())
+			git(filepath.Join(dir, "origin"), "add", ".")
+			git(filepath.Join(dir, "origin"), "commit", "-q", "-m", "Add README")
+
+			Expect(sop.Sync(filepath.Join(dir, "ops-sop"), io.Discard, io.Discard)).To(Succeed())
+
+			content, err := sop.ReadLocal(filepath.Join(dir, "ops-sop"), "openshift", "ops-sop", "README.md")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(Equal("# SOPs\n"))
+		})
+
+		It("syncs the configured clone", func() {
+			result := NewCommand().
+				Config(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).
+				Env("KITE_SOP_LOCAL_PATH", filepath.Join(dir, "ops-sop")).
+				Args("sop", "sync").
+				Run()
+
+			Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+			Expect(result.OutString()).To(ContainSubstring("is up to date"))
+		})
+
+		It("fails to sync a directory which is not a clone", func() {
+			Expect(sop.Sync(filepath.Join(dir, "origin", "v4"), io.Discard, io.Discard)).ToNot(Succeed())
+		})
+	})
+
+	It("fails to sync without a local clone", func() {
+		result := NewCommand().Config(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).Args("sop", "sync").Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("sop.local_path"))
+	})
+
+	It("is viewed in kite from a local clone without a GitHub token", func() {
+		cfg := &config.Config{SOP: &config.SOP{LocalPath: "~/git/ops-sop"}}
+
+		Expect(cfg.HasSOPs()).To(BeTrue())
+		Expect(cfg.SOPPath()).ToNot(HavePrefix("~"))
+		Expect((&config.Config{}).HasSOPs()).To(BeFalse())
+	})
+
+	Describe("cache", func() {
+		var cache *sop.Cache
+
+		BeforeEach(func() {
+			dir, err := os.MkdirTemp("", "kite-sop-cache-*")
+			Expect(err).ToNot(HaveOccurred())
+
+			cache = &sop.Cache{Dir: dir}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(cache.Dir)).To(Succeed())
+		})
+
+		It("returns the cached documents by owner, repo, ref and path", func() {
+			fetchedAt := time.Now().Add(-time.Hour).UTC()
+			document := &sop.CachedDocument{Content: "# ClusterDown\n", ETag: `"abc"`, FetchedAt: fetchedAt}
+
+			Expect(cache.Put("openshift", "ops-sop", "release/4.12", "v4/alerts/ClusterDown.md", document)).To(Succeed())
+
+			cached, err := cache.Get("openshift", "ops-sop", "release/4.12", "v4/alerts/ClusterDown.md")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cached.Content).To(Equal("# ClusterDown\n"))
+			Expect(cached.ETag).To(Equal(`"abc"`))
+			Expect(cached.FetchedAt.Equal(fetchedAt)).To(BeTrue())
+
+			_, err = cache.Get("openshift", "ops-sop", "master", "v4/alerts/ClusterDown.md")
+
+			Expect(errors.Is(err, os.ErrNotExist)).To(BeTrue())
+		})
+
+		It("doesn't store the documents outside of the cache", func() {
+			document := &sop.CachedDocument{Content: "# ClusterDown\n"}
+
+			Expect(cache.Put("openshift", "ops-sop", "master", "../../../ClusterDown.md", document)).To(MatchError(ContainSubstring("invalid document path")))
+			Expect(cache.Put("..", "ops-sop", "master", "ClusterDown.md", document)).To(MatchError(ContainSubstring("invalid repository")))
+		})
+
+		It("describes the freshness of the SOPs", func() {
+			now := time.Now()
+
+			Expect((&utils.SOPDocument{Source: utils.SOPSourceLocal}).Freshness(now)).To(Equal("local clone"))
+			Expect((&utils.SOPDocument{Source: utils.SOPSourceGitHub, FetchedAt: now}).Freshness(now)).To(Equal("fetched now"))
+			Expect((&utils.SOPDocument{Source: utils.SOPSourceCache, FetchedAt: now.Add(-12 * time.Minute)}).Freshness(now)).To(Equal("cached 12m ago"))
+			Expect((&utils.SOPDocument{Source: utils.SOPSourceCache, FetchedAt: now.Add(-3 * time.Hour), Stale: "offline"}).Freshness(now)).To(Equal("offline, cached 3h ago"))
+		})
+
+		It("is validated with GitHub once older than the max age", func() {
+			Expect((&config.Config{}).SOPMaxAge()).To(Equal(time.Hour))
+			Expect((&config.Config{SOP: &config.SOP{MaxAge: "24h"}}).SOPMaxAge()).To(Equal(24 * time.Hour))
+		})
+
+		It("is viewed in kite offline without a GitHub token", func() {
+			Expect((&config.Config{SOP: &config.SOP{Offline: true}}).HasSOPs()).To(BeTrue())
+		})
+
+		It("sets the max age and the offline mode", func() {
+			result := NewCommand().Config(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).Args("config", "set", "sop.max_age", "30m").Run()
+
+			Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+
+			result = NewCommand().Config(result.ConfigString()).Args("config", "set", "sop.offline", "true").Run()
+
+			Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+			Expect(result.ConfigString()).To(MatchJSON(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu", "sop": {"max_age": "30m", "offline": true}}`))
+
+			result = NewCommand().Config(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).Args("config", "set", "sop.max_age", "an hour").Run()
+
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("is not a duration"))
+		})
+
+		It("can't download the search index offline", func() {
+			result := NewCommand().
+				Config(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).
+				Env("XDG_CACHE_HOME", cache.Dir).
+				Args("sop", "search", "--offline", "--refresh", "ClusterDown").
+				Run()
+
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("offline"))
+		})
+	})
+
+	Describe("search", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+
+			dir, err = os.MkdirTemp("", "kite-sop-search-*")
+			Expect(err).ToNot(HaveOccurred())
+
+			documents := map[string]string{
+				"v4/alerts/ClusterDown.md":        "# ClusterDown\n\nThe cluster API is unreachable.\n\n## Troubleshooting\n\nCheck the etcd members.\n",
+				"v4/alerts/EtcdMembersDown.md":    "# EtcdMembersDown\n\nOne of the etcd members is down, the cluster is degraded.\n",
+				"v4/howto/etcd-backup.md":         "# Backup\n\nHow to back up etcd.\n",
+				"v4/alerts/KubeAPIErrorBudget.md": "# KubeAPIErrorBudgetBurn\n\nThe API server errors burn the budget.\n",
+				".git/ignored.md":                 "# etcd\n",
+				"README.txt":                      "etcd\n",
+			}
+
+			for name, content := range documents {
+				file := filepath.Join(dir, filepath.FromSlash(name))
+				Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+				Expect(os.WriteFile(file, []byte(content), 0644)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("indexes the markdown documents, titled by their first heading", func() {
+			index, err := sop.NewIndex(dir)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(index.Documents).To(HaveLen(4))
+			Expect(index.Documents[0].Title).To(Equal("ClusterDown"))
+		})
+
+		It("ranks the title and heading matches first", func() {
+			index, err := sop.NewIndex(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			results := index.Search("etcd", 0)
+
+			Expect(results).To(HaveLen(3))
+			Expect(results[0].Path).To(Equal("v4/alerts/EtcdMembersDown.md"))
+			Expect(results[1].Path).To(Equal("v4/howto/etcd-backup.md"))
+			Expect(results[2].Path).To(Equal("v4/alerts/ClusterDown.md"))
+			Expect(results[2].Snippets).To(Equal([]string{"L7: Check the etcd members."}))
+			Expect(results[2].URL()).To(Equal("https://github.com/openshift/ops-sop/blob/master/v4/alerts/ClusterDown.md"))
+		})
+
+		It("only returns the documents matching every term", func() {
+			index, err := sop.NewIndex(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			results := index.Search("etcd degraded", 0)
+
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Path).To(Equal("v4/alerts/EtcdMembersDown.md"))
+		})
+
+		It("limits the number of results", func() {
+			index, err := sop.NewIndex(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(index.Search("etcd", 2)).To(HaveLen(2))
+			Expect(index.Search("  ", 2)).To(BeEmpty())
+		})
+
+		It("cuts the snippets of the lines changing length when lowercased", func() {
+			line := strings.Repeat("Ⱥ", 60) + " İ etcd members"
+			index := &sop.Index{Documents: []sop.Document{{Path: "v4/alerts/Unicode.md", Title: "Unicode", Content: "# Unicode\n\n" + line + "\n"}}}
+
+			results := index.Search("etcd", 0)
+
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Snippets).To(HaveLen(1))
+			Expect(results[0].Snippets[0]).To(HavePrefix("L3: ..."))
+			Expect(results[0].Snippets[0]).To(HaveSuffix("Ⱥ İ etcd members"))
+		})
+
+		It("prints the results of the local clone", func() {
+			result := NewCommand().
+				Config(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).
+				Env("KITE_SOP_LOCAL_PATH", dir).
+				Args("sop", "search", "api", "budget").
+				Run()
+
+			Expect(result.ExitCode()).To(BeZero(), result.ErrString())
+			Expect(result.OutString()).To(ContainSubstring("1. KubeAPIErrorBudgetBurn (v4/alerts/KubeAPIErrorBudget.md)"))
+			Expect(result.OutString()).To(ContainSubstring("L3: The API server errors burn the budget."))
+		})
+	})
+
+	It("can't be searched without a GitHub token or a local clone", func() {
+		result := NewCommand().
+			Config(`{"version": 1, "api_key": "y_NbAkKc66ryYTWUXYEu"}`).
+			Env("XDG_CACHE_HOME", os.TempDir()+"/kite-missing-cache").
+			Args("sop", "search", "etcd").
+			Run()
+
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("sop.local_path"))
+	})
+})
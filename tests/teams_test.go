@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/teams"
 	mockpd "github.com/openshift/pagerduty-short-circuiter/pkg/client/mock"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 )
 
 var _ = Describe("select team", func() {
@@ -27,7 +28,7 @@ var _ = Describe("select team", func() {
 	})
 
 	When("kite teams is run", func() {
-		It("prompts the user to select a team and retuns the team ID", func() {
+		It("prompts the user to select a team and retuns the team", func() {
 
 			userResponse := &pdApi.User{
 				Name: "my-user",
@@ -38,8 +39,7 @@ var _ = Describe("select team", func() {
 				},
 			}
 
-			expectedTeamID := "EFGH456"
-			expectedTeamName := "my-team-b"
+			expectedTeams := []config.Team{{ID: "EFGH456", Name: "my-team-b"}}
 
 			mockClient.EXPECT().GetCurrentUser(gomock.Any()).Return(userResponse, nil).Times(1)
 
@@ -47,13 +47,42 @@ var _ = Describe("select team", func() {
 
 			stdin.Write([]byte("2\n"))
 
-			teamID, teamName, err := teams.SelectTeam(mockClient, &stdin)
+			selectedTeams, err := teams.SelectTeams(mockClient, &stdin)
 
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(teamID).To(Equal(expectedTeamID))
+			Expect(selectedTeams).To(Equal(expectedTeams))
+		})
+	})
+
+	When("a user selects more than one team", func() {
+		It("retuns all the selected teams without duplicates", func() {
+
+			userResponse := &pdApi.User{
+				Name: "my-user",
+				Teams: []pdApi.Team{
+					{APIObject: pdApi.APIObject{ID: "ABCD123", Summary: "my-team-a"}},
+					{APIObject: pdApi.APIObject{ID: "EFGH456", Summary: "my-team-b"}},
+					{APIObject: pdApi.APIObject{ID: "IJKL789", Summary: "my-team-c"}},
+				},
+			}
 
-			Expect(teamName).To(Equal(expectedTeamName))
+			expectedTeams := []config.Team{
+				{ID: "IJKL789", Name: "my-team-c"},
+				{ID: "ABCD123", Name: "my-team-a"},
+			}
+
+			mockClient.EXPECT().GetCurrentUser(gomock.Any()).Return(userResponse, nil).Times(1)
+
+			var stdin bytes.Buffer
+
+			stdin.Write([]byte("3, 1 3\n"))
+
+			selectedTeams, err := teams.SelectTeams(mockClient, &stdin)
+
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(selectedTeams).To(Equal(expectedTeams))
 		})
 	})
 
@@ -75,13 +104,11 @@ var _ = Describe("select team", func() {
 
 			stdin.Write([]byte("X\n"))
 
-			teamID, teamName, err := teams.SelectTeam(mockClient, &stdin)
+			selectedTeams, err := teams.SelectTeams(mockClient, &stdin)
 
 			Expect(err).To(HaveOccurred())
 
-			Expect(teamID).To(BeEmpty())
-
-			Expect(teamName).To(BeEmpty())
+			Expect(selectedTeams).To(BeEmpty())
 		})
 	})
 })